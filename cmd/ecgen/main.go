@@ -0,0 +1,419 @@
+// Command ecgen generates Go code of an error code package
+// (constants, string converters, symbolic names and group registration)
+// from a declarative YAML spec, so that the constants and their descriptions
+// can't drift apart.
+//
+// It is intended to be run with go generate from the directory
+// of the package, e.g.:
+//
+//	//go:generate go run github.com/iotanbo/igu/cmd/ecgen
+//
+// By default it reads codes.yaml and writes codes_gen.go and codes_gen_test.go,
+// the latter checks that the codes are unique and lie within the range.
+// Spec example:
+//
+//	# Package the code is generated for.
+//	package: ecfs
+//	# Name of the group, either a constant of the ecdef package or a literal.
+//	group: FS_GROUP
+//	# Range of the group, either constants of the ecdef package or numbers.
+//	begin: FS_RANGE_BEGIN
+//	end: FS_RANGE_END
+//	# Format of the description of unknown codes (optional).
+//	unknown: "unknown ecfs error code (%d)"
+//	codes:
+//	  - name: Error
+//	    doc: Error is a generic file system error that does not provide extra details.
+//	    text: file system error
+//	  - name: NotAFile
+//	    doc: Entity is not a file.
+//	    text: not a file
+//
+// Codes are numbered sequentially starting from begin, a code may specify
+// its own absolute value (e.g. "value: 404"), then the following codes
+// continue from it. New codes must only be appended so that existing
+// codes keep their values.
+//
+// Application-specific codes (APP_RANGE_BEGIN..APP_RANGE_END) must be
+// generated with "register: false" since the app group is registered
+// by the errs package; assign the generated converters to
+// errs.AppECToString, errs.AppECName and errs.AppECCodes
+// at program start instead.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/iotanbo/igu/pkg/ecdef"
+	"gopkg.in/yaml.v3"
+)
+
+// Spec describes an error code package.
+type Spec struct {
+	Package  string `yaml:"package"`
+	Group    string `yaml:"group"`
+	Begin    string `yaml:"begin"`
+	End      string `yaml:"end"`
+	Unknown  string `yaml:"unknown"`
+	Register *bool  `yaml:"register"`
+	Codes    []Code `yaml:"codes"`
+}
+
+// Code describes a single error code.
+type Code struct {
+	Name  string `yaml:"name"`
+	Doc   string `yaml:"doc"`
+	Text  string `yaml:"text"`
+	Value *int64 `yaml:"value"`
+}
+
+// Constants of the ecdef package that can be referred to in specs.
+var ecdefGroups = map[string]string{
+	"BASIC_GROUP": ecdef.BASIC_GROUP,
+	"HTTP_GROUP":  ecdef.HTTP_GROUP,
+	"FS_GROUP":    ecdef.FS_GROUP,
+	"AUTH_GROUP":  ecdef.AUTH_GROUP,
+	"NET_GROUP":   ecdef.NET_GROUP,
+	"DB_GROUP":    ecdef.DB_GROUP,
+	"MATH_GROUP":  ecdef.MATH_GROUP,
+	"SYS_GROUP":   ecdef.SYS_GROUP,
+	"APP_GROUP":   ecdef.APP_GROUP,
+}
+
+var ecdefRanges = map[string]ecdef.ErrCode{
+	"BASIC_RANGE_BEGIN": ecdef.BASIC_RANGE_BEGIN,
+	"BASIC_RANGE_END":   ecdef.BASIC_RANGE_END,
+	"HTTP_RANGE_BEGIN":  ecdef.HTTP_RANGE_BEGIN,
+	"HTTP_RANGE_END":    ecdef.HTTP_RANGE_END,
+	"FS_RANGE_BEGIN":    ecdef.FS_RANGE_BEGIN,
+	"FS_RANGE_END":      ecdef.FS_RANGE_END,
+	"AUTH_RANGE_BEGIN":  ecdef.AUTH_RANGE_BEGIN,
+	"AUTH_RANGE_END":    ecdef.AUTH_RANGE_END,
+	"NET_RANGE_BEGIN":   ecdef.NET_RANGE_BEGIN,
+	"NET_RANGE_END":     ecdef.NET_RANGE_END,
+	"DB_RANGE_BEGIN":    ecdef.DB_RANGE_BEGIN,
+	"DB_RANGE_END":      ecdef.DB_RANGE_END,
+	"MATH_RANGE_BEGIN":  ecdef.MATH_RANGE_BEGIN,
+	"MATH_RANGE_END":    ecdef.MATH_RANGE_END,
+	"SYS_RANGE_BEGIN":   ecdef.SYS_RANGE_BEGIN,
+	"SYS_RANGE_END":     ecdef.SYS_RANGE_END,
+	"APP_RANGE_BEGIN":   ecdef.APP_RANGE_BEGIN,
+	"APP_RANGE_END":     ecdef.APP_RANGE_END,
+}
+
+func main() {
+	specPath := flag.String("spec", "codes.yaml", "path to the spec file")
+	outPath := flag.String("out", "codes_gen.go", "path to the generated code")
+	testPath := flag.String("test", "codes_gen_test.go",
+		"path to the generated test, empty to skip")
+	flag.Parse()
+
+	if err := run(*specPath, *outPath, *testPath); err != nil {
+		fmt.Fprintf(os.Stderr, "ecgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(specPath, outPath, testPath string) error {
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return err
+	}
+	code, test, err := Generate(data, specPath)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(outPath, code, 0644); err != nil {
+		return err
+	}
+	if testPath != "" {
+		return os.WriteFile(testPath, test, 0644)
+	}
+	return nil
+}
+
+// Generate parses the spec and returns formatted source of the package code
+// and of its test; specName is only used in the generated header.
+func Generate(data []byte, specName string) (code, test []byte, err error) {
+	var spec Spec
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&spec); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", specName, err)
+	}
+	m, err := newModel(&spec)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", specName, err)
+	}
+	m.SpecName = specName
+	if code, err = execute(codeTemplate, m); err != nil {
+		return nil, nil, err
+	}
+	if test, err = execute(testTemplate, m); err != nil {
+		return nil, nil, err
+	}
+	return code, test, nil
+}
+
+// model is the data passed to the templates.
+type model struct {
+	SpecName  string
+	Package   string
+	GroupExpr string
+	BeginExpr string
+	EndExpr   string
+	Unknown   string
+	Register  bool
+	Codes     []modelCode
+}
+
+type modelCode struct {
+	Name  string
+	Doc   []string
+	Text  string
+	Value ecdef.ErrCode
+}
+
+// newModel validates the spec and computes the values of the codes.
+func newModel(spec *Spec) (*model, error) {
+	if !token.IsIdentifier(spec.Package) {
+		return nil, fmt.Errorf("invalid package name '%s'", spec.Package)
+	}
+	m := &model{Package: spec.Package, Register: true, Unknown: spec.Unknown}
+	if spec.Register != nil {
+		m.Register = *spec.Register
+	}
+	if m.Unknown == "" {
+		m.Unknown = fmt.Sprintf("unknown %s error code (%%d)", spec.Package)
+	}
+
+	if _, ok := ecdefGroups[spec.Group]; ok {
+		m.GroupExpr = "ecdef." + spec.Group
+	} else if spec.Group != "" {
+		m.GroupExpr = strconv.Quote(spec.Group)
+	} else if m.Register {
+		return nil, fmt.Errorf("group name must not be empty")
+	}
+	begin, beginExpr, err := rangeValue(spec.Begin, "begin")
+	if err != nil {
+		return nil, err
+	}
+	end, endExpr, err := rangeValue(spec.End, "end")
+	if err != nil {
+		return nil, err
+	}
+	if begin > end {
+		return nil, fmt.Errorf("invalid range [%d, %d]", begin, end)
+	}
+	m.BeginExpr, m.EndExpr = beginExpr, endExpr
+
+	if strings.Count(m.Unknown, "%d") != 1 {
+		return nil, fmt.Errorf("unknown code format '%s' must contain one %%d", m.Unknown)
+	}
+	if len(spec.Codes) == 0 {
+		return nil, fmt.Errorf("no codes defined")
+	}
+	names := map[string]bool{}
+	values := map[ecdef.ErrCode]string{}
+	next := int64(begin)
+	for _, c := range spec.Codes {
+		if !token.IsIdentifier(c.Name) || !token.IsExported(c.Name) {
+			return nil, fmt.Errorf("invalid code name '%s'", c.Name)
+		}
+		if names[c.Name] {
+			return nil, fmt.Errorf("duplicate code name '%s'", c.Name)
+		}
+		names[c.Name] = true
+		if c.Text == "" {
+			return nil, fmt.Errorf("code '%s' has no text", c.Name)
+		}
+		if c.Value != nil {
+			next = *c.Value
+		}
+		if next < int64(begin) || next > int64(end) {
+			return nil, fmt.Errorf("value %d of code '%s' is out of range [%d, %d]",
+				next, c.Name, begin, end)
+		}
+		value := ecdef.ErrCode(next)
+		if other, ok := values[value]; ok {
+			return nil, fmt.Errorf("codes '%s' and '%s' have the same value %d",
+				other, c.Name, value)
+		}
+		values[value] = c.Name
+		m.Codes = append(m.Codes, modelCode{
+			Name:  c.Name,
+			Doc:   docLines(c.Doc),
+			Text:  c.Text,
+			Value: value,
+		})
+		next++
+	}
+	return m, nil
+}
+
+// rangeValue resolves a range bound given either as a constant
+// of the ecdef package or as a number.
+func rangeValue(s, what string) (ecdef.ErrCode, string, error) {
+	if v, ok := ecdefRanges[s]; ok {
+		return v, "ecdef." + s, nil
+	}
+	v, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid range %s '%s'", what, s)
+	}
+	return ecdef.ErrCode(v), s, nil
+}
+
+func docLines(doc string) []string {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return nil
+	}
+	return strings.Split(doc, "\n")
+}
+
+func execute(t *template.Template, m *model) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, m); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("can't format generated code: %w", err)
+	}
+	return src, nil
+}
+
+var funcs = template.FuncMap{"quote": strconv.Quote}
+
+var codeTemplate = template.Must(template.New("code").Funcs(funcs).Parse(
+	`// Code generated by ecgen from {{.SpecName}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"fmt"
+
+	"github.com/iotanbo/igu/pkg/ecdef"
+)
+
+const (
+{{- range .Codes}}
+{{- range .Doc}}
+	// {{.}}
+{{- end}}
+	{{.Name}} ecdef.ErrCode = {{.Value}}
+{{- end}}
+)
+{{if .Register}}
+func init() {
+	ecdef.MustRegisterGroup(ecdef.Group{
+		Name:     {{.GroupExpr}},
+		Begin:    {{.BeginExpr}},
+		End:      {{.EndExpr}},
+		ToString: ECToString,
+		CodeName: ECName,
+		Codes:    ECCodes,
+	})
+}
+{{end}}
+// ECToString(...) returns a string describing an {{.Package}} error code.
+func ECToString(errCode ecdef.ErrCode) string {
+	switch errCode {
+{{- range .Codes}}
+	case {{.Name}}:
+		return {{quote .Text}}
+{{- end}}
+	}
+	return fmt.Sprintf({{quote .Unknown}}, errCode)
+}
+
+// ECName(...) returns the symbolic name of an {{.Package}} error code,
+// e.g. "{{.Package}}.{{(index .Codes 0).Name}}", or an empty string if the code is unknown.
+func ECName(errCode ecdef.ErrCode) string {
+	switch errCode {
+{{- range .Codes}}
+	case {{.Name}}:
+		return "{{$.Package}}.{{.Name}}"
+{{- end}}
+	}
+	return ""
+}
+
+// ECCodes(...) returns all {{.Package}} error codes in the order of definition.
+func ECCodes() []ecdef.ErrCode {
+	return []ecdef.ErrCode{
+{{- range .Codes}}
+		{{.Name}},
+{{- end}}
+	}
+}
+`))
+
+var testTemplate = template.Must(template.New("test").Funcs(funcs).Parse(
+	`// Code generated by ecgen from {{.SpecName}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/iotanbo/igu/pkg/ecdef"
+)
+
+// All codes defined in {{.SpecName}}.
+var specCodes = []ecdef.ErrCode{
+{{- range .Codes}}
+	{{.Name}},
+{{- end}}
+}
+
+func TestGeneratedCodes(t *testing.T) {
+	names := map[string]bool{}
+	values := map[ecdef.ErrCode]string{}
+	for _, code := range specCodes {
+		name := ECName(code)
+		if name == "" {
+			t.Errorf("code %d has no name", code)
+		}
+		if names[name] {
+			t.Errorf("duplicate code name %s", name)
+		}
+		names[name] = true
+		if other, ok := values[code]; ok {
+			t.Errorf("%s and %s have the same value %d", other, name, code)
+		}
+		values[code] = name
+		if code < {{.BeginExpr}} || code > {{.EndExpr}} {
+			t.Errorf("%s (%d) is out of range [%d, %d]", name, code,
+				{{.BeginExpr}}, {{.EndExpr}})
+		}
+		if ECToString(code) == fmt.Sprintf({{quote .Unknown}}, code) {
+			t.Errorf("%s has no description", name)
+		}
+{{- if .Register}}
+		if g, ok := ecdef.GroupOf(code); !ok || g.Name != {{.GroupExpr}} {
+			t.Errorf("%s is not in the registered group %s", name, {{.GroupExpr}})
+		}
+		if ecdef.CodeName(code) != name {
+			t.Errorf("ecdef.CodeName(%s) returns %s", name, ecdef.CodeName(code))
+		}
+		if parsed, ok := ecdef.ParseCodeName(name); !ok || parsed != code {
+			t.Errorf("ecdef.ParseCodeName(%s) returns %d, %v", name, parsed, ok)
+		}
+{{- end}}
+	}
+	if len(ECCodes()) != len(specCodes) {
+		t.Errorf("ECCodes() returns %d codes, expected %d", len(ECCodes()), len(specCodes))
+	}
+}
+`))
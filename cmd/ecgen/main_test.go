@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	spec := `
+package: ectest
+group: test
+begin: 2000000000
+end: 2000000009
+codes:
+  - name: Error
+    doc: Generic test error.
+    text: test error
+  - name: Second
+    text: second error
+  - name: Fifth
+    value: 2000000005
+    text: fifth error
+  - name: Sixth
+    text: sixth error
+`
+	code, test, err := Generate([]byte(spec), "spec.yaml")
+	require.Nil(t, err, "%v", err)
+	src := string(code)
+	require.Contains(t, src, "// Code generated by ecgen from spec.yaml. DO NOT EDIT.")
+	require.Regexp(t, `Second +ecdef.ErrCode = 2000000001`, src)
+	require.Regexp(t, `Sixth +ecdef.ErrCode = 2000000006`, src)
+	require.Contains(t, src, `Name:     "test",`)
+	require.Contains(t, src, `return "ectest.Fifth"`)
+	require.Contains(t, src, `"unknown ectest error code (%d)"`)
+	require.Contains(t, string(test), "func TestGeneratedCodes(t *testing.T)")
+
+	// Unregistered (app-specific) codes
+	code, _, err = Generate([]byte(strings.Replace(spec, "group: test",
+		"register: false", 1)), "spec.yaml")
+	require.Nil(t, err, "%v", err)
+	require.NotContains(t, string(code), "MustRegisterGroup")
+}
+
+func TestGenerateInvalidSpec(t *testing.T) {
+	header := "package: ectest\ngroup: test\nbegin: 10\nend: 12\n"
+	var testData = []struct {
+		spec, errText string
+	}{
+		{header + "codes:\n  - {name: A, text: a}\n  - {name: A, text: b}\n",
+			"duplicate code name 'A'"},
+		{header + "codes:\n  - {name: A, text: a}\n  - {name: B, value: 10, text: b}\n",
+			"codes 'A' and 'B' have the same value 10"},
+		{header + "codes:\n  - {name: A, value: 13, text: a}\n",
+			"out of range"},
+		{header + "codes:\n  - {name: A, text: a}\n  - {name: B, text: b}\n" +
+			"  - {name: C, text: c}\n  - {name: D, text: d}\n", "out of range"},
+		{header + "codes:\n  - {name: a, text: a}\n", "invalid code name"},
+		{header + "codes:\n  - {name: A}\n", "has no text"},
+		{header + "codes:\n  - {name: A, text: a, descr: a}\n", "field descr not found"},
+		{"package: ectest\ngroup: test\nbegin: 12\nend: 10\ncodes:\n  - {name: A, text: a}\n",
+			"invalid range"},
+		{"package: ectest\ngroup: test\nbegin: NO_SUCH_BEGIN\nend: 10\n", "invalid range begin"},
+		{"package: ectest\nbegin: 10\nend: 12\ncodes:\n  - {name: A, text: a}\n",
+			"group name must not be empty"},
+	}
+	for _, td := range testData {
+		_, _, err := Generate([]byte(td.spec), "spec.yaml")
+		require.NotNil(t, err, "spec must be rejected:\n%s", td.spec)
+		require.Contains(t, err.Error(), td.errText)
+	}
+}
+
+// Generated code of IGU error code packages must be up to date with the specs.
+func TestGeneratedUpToDate(t *testing.T) {
+	specs, err := filepath.Glob("../../pkg/*/codes.yaml")
+	require.Nil(t, err)
+	require.NotEmpty(t, specs)
+	for _, spec := range specs {
+		data, err := os.ReadFile(spec)
+		require.Nil(t, err)
+		code, test, err := Generate(data, "codes.yaml")
+		require.Nil(t, err, "%v", err)
+		dir := filepath.Dir(spec)
+		actual, err := os.ReadFile(filepath.Join(dir, "codes_gen.go"))
+		require.Nil(t, err)
+		require.True(t, string(code) == string(actual),
+			"%s/codes_gen.go is out of date, run 'go generate'", dir)
+		actual, err = os.ReadFile(filepath.Join(dir, "codes_gen_test.go"))
+		require.Nil(t, err)
+		require.True(t, string(test) == string(actual),
+			"%s/codes_gen_test.go is out of date, run 'go generate'", dir)
+	}
+}
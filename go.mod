@@ -1,15 +1,11 @@
 module github.com/iotanbo/igu
 
-go 1.17
-
-replace github.com/iotanbo/copy => /C/ASSETS/GO/VENDOR/copy
+go 1.19
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	//github.com/otiai10/copy v1.6.0
-	github.com/iotanbo/copy v1.6.0
 	github.com/stretchr/testify v1.7.0
-	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 )
 
 // github.com/cosiner/argv v0.1.0
@@ -17,6 +13,13 @@ require github.com/pmezard/go-difflib v1.0.0 // indirect
 
 require github.com/mholt/archiver/v3 v3.5.0
 
+require (
+	golang.org/x/sys v0.21.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237
+	google.golang.org/grpc v1.64.1
+	google.golang.org/protobuf v1.33.0
+)
+
 require (
 	github.com/andybalholm/brotli v1.0.3 // indirect
 	github.com/dsnet/compress v0.0.1 // indirect
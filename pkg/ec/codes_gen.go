@@ -0,0 +1,270 @@
+// Code generated by ecgen from codes.yaml. DO NOT EDIT.
+
+package ec
+
+import (
+	"fmt"
+
+	"github.com/iotanbo/igu/pkg/ecdef"
+)
+
+const (
+	// Zero error code, means no error.
+	NoError ecdef.ErrCode = 0
+	// Entity not found.
+	NotFound ecdef.ErrCode = 1
+	// Operation lacked the necessary privileges to complete.
+	PermissionDenied ecdef.ErrCode = 2
+	// Operation failed because a pipe was closed.
+	BrokenPipe ecdef.ErrCode = 3
+	// Entity already exists, often a file.
+	AlreadyExists ecdef.ErrCode = 4
+	// Entity already closed, often a file.
+	AlreadyClosed ecdef.ErrCode = 5
+	// Operation needs to block to complete, but the blocking was requested to not occur.
+	WouldBlock ecdef.ErrCode = 6
+	// Parameter was incorrect.
+	InvalidInput ecdef.ErrCode = 7
+	// Invalid data source, e.g. file’s contents expected to be UTF-8 but is not.
+	InvalidData ecdef.ErrCode = 8
+	// Operation timed out, causing it to be canceled.
+	TimedOut ecdef.ErrCode = 9
+	// Call to write returned Ok(0), no more data can be written at the moment.
+	WriteZero ecdef.ErrCode = 10
+	// Operation was interrupted (and typically can be retried).
+	Interrupted ecdef.ErrCode = 11
+	// Other is the error code to be returned from a function
+	// to signify an error that was not expected by normal flow control;
+	// If the error is critical, use panic instead.
+	Other ecdef.ErrCode = 12
+	// “end of file” was reached prematurely.
+	UnexpectedEof ecdef.ErrCode = 13
+	// Unsupported on current platform.
+	Unsupported ecdef.ErrCode = 14
+	// Assertion error.
+	Assertion ecdef.ErrCode = 15
+	// Specified index is out of range.
+	Index ecdef.ErrCode = 16
+	// Specified key not exists or is invalid.
+	Key ecdef.ErrCode = 17
+	// Memory corruption or allocation failure.
+	Memory ecdef.ErrCode = 18
+	// Entity not implemented.
+	NotImplemented ecdef.ErrCode = 19
+	// Generic recursion error (not allowed, endless recursion etc.).
+	Recursion ecdef.ErrCode = 20
+	// Generic syntax error.
+	Syntax ecdef.ErrCode = 21
+	// Type is invalid.
+	Type ecdef.ErrCode = 22
+	// Value is invalid.
+	Value ecdef.ErrCode = 23
+	// Dummy error code used for testing and benchmarking.
+	Dummy ecdef.ErrCode = 24
+	// Windows-specific error when unix symlink is found on disk.
+	SymlinksNotSupported ecdef.ErrCode = 25
+	// Process exited with error.
+	ProcessExit ecdef.ErrCode = 26
+	// Result already achieved or job can't be done.
+	NothingDone ecdef.ErrCode = 27
+	// Several errors occurred, see errs.MultiErr.
+	Multiple ecdef.ErrCode = 28
+	// A panic was recovered and converted into an error, see errs.Recover().
+	Panic ecdef.ErrCode = 29
+	// Operation was canceled by the caller, e.g. its context was canceled.
+	Canceled ecdef.ErrCode = 30
+	// Deadline of the operation (e.g. of its context) passed before it completed.
+	DeadlineExceeded ecdef.ErrCode = 31
+)
+
+func init() {
+	ecdef.MustRegisterGroup(ecdef.Group{
+		Name:     ecdef.BASIC_GROUP,
+		Begin:    ecdef.BASIC_RANGE_BEGIN,
+		End:      ecdef.BASIC_RANGE_END,
+		ToString: ECToString,
+		CodeName: ECName,
+		Codes:    ECCodes,
+	})
+}
+
+// ECToString(...) returns a string describing an ec error code.
+func ECToString(errCode ecdef.ErrCode) string {
+	switch errCode {
+	case NoError:
+		return "ec.NoError"
+	case NotFound:
+		return "ec.NotFound"
+	case PermissionDenied:
+		return "ec.PermissionDenied"
+	case BrokenPipe:
+		return "ec.BrokenPipe"
+	case AlreadyExists:
+		return "ec.AlreadyExists"
+	case AlreadyClosed:
+		return "ec.AlreadyClosed"
+	case WouldBlock:
+		return "ec.WouldBlock (operation needs to block to complete)"
+	case InvalidInput:
+		return "ec.InvalidInput (parameter was incorrect)"
+	case InvalidData:
+		return "ec.InvalidData (invalid source data)"
+	case TimedOut:
+		return "ec.TimedOut"
+	case WriteZero:
+		return "ec.WriteZero (no more data can be written at the moment)"
+	case Interrupted:
+		return "ec.Interrupted"
+	case Other:
+		return "ec.Other (other error)"
+	case UnexpectedEof:
+		return "ec.UnexpectedEof (EOF reached prematurely)"
+	case Unsupported:
+		return "ec.Unsupported"
+	case Assertion:
+		return "ec.Assertion (assertion error)"
+	case Index:
+		return "ec.Index (index error)"
+	case Key:
+		return "ec.Key (key error)"
+	case Memory:
+		return "ec.Memory (memory error)"
+	case NotImplemented:
+		return "ec.NotImplemented"
+	case Recursion:
+		return "ec.Recursion (recursion error)"
+	case Syntax:
+		return "ec.Syntax (syntax error)"
+	case Type:
+		return "ec.Type (type error)"
+	case Value:
+		return "ec.Value (value error)"
+	case Dummy:
+		return "ec.Dummy (dummy error for testing purpose)"
+	case SymlinksNotSupported:
+		return "ec.SymlinksNotSupported (Windows?)"
+	case ProcessExit:
+		return "ec.ProcessExit (process exited with error)"
+	case NothingDone:
+		return "ec.NothingDone (result already achieved or job can't be done)"
+	case Multiple:
+		return "ec.Multiple (multiple errors)"
+	case Panic:
+		return "ec.Panic (recovered panic)"
+	case Canceled:
+		return "ec.Canceled (operation canceled)"
+	case DeadlineExceeded:
+		return "ec.DeadlineExceeded (deadline exceeded)"
+	}
+	return fmt.Sprintf("unknown basic error code (%d)", errCode)
+}
+
+// ECName(...) returns the symbolic name of an ec error code,
+// e.g. "ec.NoError", or an empty string if the code is unknown.
+func ECName(errCode ecdef.ErrCode) string {
+	switch errCode {
+	case NoError:
+		return "ec.NoError"
+	case NotFound:
+		return "ec.NotFound"
+	case PermissionDenied:
+		return "ec.PermissionDenied"
+	case BrokenPipe:
+		return "ec.BrokenPipe"
+	case AlreadyExists:
+		return "ec.AlreadyExists"
+	case AlreadyClosed:
+		return "ec.AlreadyClosed"
+	case WouldBlock:
+		return "ec.WouldBlock"
+	case InvalidInput:
+		return "ec.InvalidInput"
+	case InvalidData:
+		return "ec.InvalidData"
+	case TimedOut:
+		return "ec.TimedOut"
+	case WriteZero:
+		return "ec.WriteZero"
+	case Interrupted:
+		return "ec.Interrupted"
+	case Other:
+		return "ec.Other"
+	case UnexpectedEof:
+		return "ec.UnexpectedEof"
+	case Unsupported:
+		return "ec.Unsupported"
+	case Assertion:
+		return "ec.Assertion"
+	case Index:
+		return "ec.Index"
+	case Key:
+		return "ec.Key"
+	case Memory:
+		return "ec.Memory"
+	case NotImplemented:
+		return "ec.NotImplemented"
+	case Recursion:
+		return "ec.Recursion"
+	case Syntax:
+		return "ec.Syntax"
+	case Type:
+		return "ec.Type"
+	case Value:
+		return "ec.Value"
+	case Dummy:
+		return "ec.Dummy"
+	case SymlinksNotSupported:
+		return "ec.SymlinksNotSupported"
+	case ProcessExit:
+		return "ec.ProcessExit"
+	case NothingDone:
+		return "ec.NothingDone"
+	case Multiple:
+		return "ec.Multiple"
+	case Panic:
+		return "ec.Panic"
+	case Canceled:
+		return "ec.Canceled"
+	case DeadlineExceeded:
+		return "ec.DeadlineExceeded"
+	}
+	return ""
+}
+
+// ECCodes(...) returns all ec error codes in the order of definition.
+func ECCodes() []ecdef.ErrCode {
+	return []ecdef.ErrCode{
+		NoError,
+		NotFound,
+		PermissionDenied,
+		BrokenPipe,
+		AlreadyExists,
+		AlreadyClosed,
+		WouldBlock,
+		InvalidInput,
+		InvalidData,
+		TimedOut,
+		WriteZero,
+		Interrupted,
+		Other,
+		UnexpectedEof,
+		Unsupported,
+		Assertion,
+		Index,
+		Key,
+		Memory,
+		NotImplemented,
+		Recursion,
+		Syntax,
+		Type,
+		Value,
+		Dummy,
+		SymlinksNotSupported,
+		ProcessExit,
+		NothingDone,
+		Multiple,
+		Panic,
+		Canceled,
+		DeadlineExceeded,
+	}
+}
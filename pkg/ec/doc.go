@@ -0,0 +1,7 @@
+// Package ec (Error Codes) defines error codes of the basic error group
+// that are used in IGU library.
+//
+// Constants and converters are generated from codes.yaml by cmd/ecgen.
+package ec
+
+//go:generate go run github.com/iotanbo/igu/cmd/ecgen
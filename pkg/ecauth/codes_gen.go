@@ -0,0 +1,65 @@
+// Code generated by ecgen from codes.yaml. DO NOT EDIT.
+
+package ecauth
+
+import (
+	"fmt"
+
+	"github.com/iotanbo/igu/pkg/ecdef"
+)
+
+const (
+	// Authentication/Authorization failure that does not provide extra details.
+	Failed ecdef.ErrCode = 1000300
+	// Bad credentials.
+	Credentials ecdef.ErrCode = 1000301
+	// Proposed authentication method not supported.
+	UnsupportedMethod ecdef.ErrCode = 1000302
+)
+
+func init() {
+	ecdef.MustRegisterGroup(ecdef.Group{
+		Name:     ecdef.AUTH_GROUP,
+		Begin:    ecdef.AUTH_RANGE_BEGIN,
+		End:      ecdef.AUTH_RANGE_END,
+		ToString: ECToString,
+		CodeName: ECName,
+		Codes:    ECCodes,
+	})
+}
+
+// ECToString(...) returns a string describing an ecauth error code.
+func ECToString(errCode ecdef.ErrCode) string {
+	switch errCode {
+	case Failed:
+		return "authentication failed"
+	case Credentials:
+		return "bad credentials"
+	case UnsupportedMethod:
+		return "unsupported authentication method"
+	}
+	return fmt.Sprintf("unknown ecauth error code (%d)", errCode)
+}
+
+// ECName(...) returns the symbolic name of an ecauth error code,
+// e.g. "ecauth.Failed", or an empty string if the code is unknown.
+func ECName(errCode ecdef.ErrCode) string {
+	switch errCode {
+	case Failed:
+		return "ecauth.Failed"
+	case Credentials:
+		return "ecauth.Credentials"
+	case UnsupportedMethod:
+		return "ecauth.UnsupportedMethod"
+	}
+	return ""
+}
+
+// ECCodes(...) returns all ecauth error codes in the order of definition.
+func ECCodes() []ecdef.ErrCode {
+	return []ecdef.ErrCode{
+		Failed,
+		Credentials,
+		UnsupportedMethod,
+	}
+}
@@ -0,0 +1,7 @@
+// Package ecauth defines error codes of the authentication / authorization error group
+// that are used in IGU library.
+//
+// Constants and converters are generated from codes.yaml by cmd/ecgen.
+package ecauth
+
+//go:generate go run github.com/iotanbo/igu/cmd/ecgen
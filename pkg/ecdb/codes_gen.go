@@ -0,0 +1,51 @@
+// Code generated by ecgen from codes.yaml. DO NOT EDIT.
+
+package ecdb
+
+import (
+	"fmt"
+
+	"github.com/iotanbo/igu/pkg/ecdef"
+)
+
+const (
+	// Error is a generic database error that does not provide extra details.
+	Error ecdef.ErrCode = 1000900
+)
+
+func init() {
+	ecdef.MustRegisterGroup(ecdef.Group{
+		Name:     ecdef.DB_GROUP,
+		Begin:    ecdef.DB_RANGE_BEGIN,
+		End:      ecdef.DB_RANGE_END,
+		ToString: ECToString,
+		CodeName: ECName,
+		Codes:    ECCodes,
+	})
+}
+
+// ECToString(...) returns a string describing an ecdb error code.
+func ECToString(errCode ecdef.ErrCode) string {
+	switch errCode {
+	case Error:
+		return "database error"
+	}
+	return fmt.Sprintf("unknown ECDB error code (%d)", errCode)
+}
+
+// ECName(...) returns the symbolic name of an ecdb error code,
+// e.g. "ecdb.Error", or an empty string if the code is unknown.
+func ECName(errCode ecdef.ErrCode) string {
+	switch errCode {
+	case Error:
+		return "ecdb.Error"
+	}
+	return ""
+}
+
+// ECCodes(...) returns all ecdb error codes in the order of definition.
+func ECCodes() []ecdef.ErrCode {
+	return []ecdef.ErrCode{
+		Error,
+	}
+}
@@ -0,0 +1,7 @@
+// Package ecdb defines error codes of the database error group
+// that are used in IGU library.
+//
+// Constants and converters are generated from codes.yaml by cmd/ecgen.
+package ecdb
+
+//go:generate go run github.com/iotanbo/igu/cmd/ecgen
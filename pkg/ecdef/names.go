@@ -0,0 +1,147 @@
+package ecdef
+
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
+// CodeInfo describes an error code of a registered group.
+type CodeInfo struct {
+	// Error code.
+	Code ErrCode
+	// Symbolic name, e.g. "ecnet.ConnectionRefused".
+	Name string
+	// Name of the group the code belongs to, e.g. "ecnet".
+	Group string
+	// Description of the code as returned by CodeToString().
+	Text string
+}
+
+// ParseCodeName is the reverse of CodeName: it returns the error code
+// with specified symbolic name (e.g. "ec.NotFound") and true,
+// or NoError and false if there is no such code.
+// Names of the form "group(number)" are accepted as well
+// if the number belongs to the range of the group.
+// Only groups that provide Codes and CodeName functions are searched by name.
+func ParseCodeName(name string) (ErrCode, bool) {
+	if code, ok := parseNumericName(name); ok {
+		return code, true
+	}
+	prefix := name
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		prefix = name[:i]
+	}
+	// The prefix of a name is usually the group name
+	if g, ok := GroupByName(prefix); ok {
+		if code, ok := findCodeName(&g, name); ok {
+			return code, true
+		}
+	}
+	for _, g := range loadRegistry().groups {
+		if g.Name != prefix {
+			if code, ok := findCodeName(&g, name); ok {
+				return code, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// parseNumericName parses names like "ecfs(1000042)".
+func parseNumericName(name string) (ErrCode, bool) {
+	open := strings.IndexByte(name, '(')
+	if open <= 0 || !strings.HasSuffix(name, ")") {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(name[open+1:len(name)-1], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	code := ErrCode(n)
+	if !InGroup(code, name[:open]) {
+		return 0, false
+	}
+	return code, true
+}
+
+func findCodeName(g *Group, name string) (ErrCode, bool) {
+	if g.Codes == nil || g.CodeName == nil {
+		return 0, false
+	}
+	for _, code := range g.Codes() {
+		if g.CodeName(code) == name {
+			return code, true
+		}
+	}
+	return 0, false
+}
+
+// AllCodes returns all error codes of the registered groups
+// sorted by group range and then in the order of definition.
+// Groups that do not provide a Codes function are skipped.
+func AllCodes() []CodeInfo {
+	var r []CodeInfo
+	for _, g := range loadRegistry().groups {
+		if g.Codes == nil {
+			continue
+		}
+		for _, code := range g.Codes() {
+			r = append(r, CodeInfo{
+				Code:  code,
+				Name:  CodeName(code),
+				Group: g.Name,
+				Text:  g.ToString(code),
+			})
+		}
+	}
+	return r
+}
+
+// MatchCodes returns all error codes whose symbolic names match pattern,
+// see path.Match() for the pattern syntax. A pattern without a dot
+// is a group name and matches all codes of the group. Examples:
+//
+//	MatchCodes("ecnet.*")      // all network error codes
+//	MatchCodes("ecnet")        // same as above
+//	MatchCodes("*.NotFound")   // ec.NotFound (but not echttp.NotFound_404)
+//	MatchCodes("ecnet.Conn*")  // ecnet.ConnectionRefused, ecnet.ConnectionReset...
+//
+// Returns path.ErrBadPattern if the pattern is malformed.
+func MatchCodes(pattern string) ([]CodeInfo, error) {
+	if !strings.Contains(pattern, ".") {
+		pattern += ".*"
+	}
+	// Check the syntax even if there are no codes to match
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	var r []CodeInfo
+	for _, info := range AllCodes() {
+		if ok, _ := path.Match(pattern, info.Name); ok {
+			r = append(r, info)
+		}
+	}
+	return r, nil
+}
+
+// CodeMatches returns true if the symbolic name of code matches pattern,
+// see MatchCodes(). A malformed pattern matches nothing.
+// Codes without a symbolic name match only patterns like "ecfs" or "ecfs.*".
+func CodeMatches(code ErrCode, pattern string) bool {
+	if group, ok := groupPattern(pattern); ok {
+		return InGroup(code, group)
+	}
+	ok, _ := path.Match(pattern, CodeName(code))
+	return ok
+}
+
+// groupPattern returns the group name if pattern matches a whole group,
+// i.e. it is either "group" or "group.*".
+func groupPattern(pattern string) (string, bool) {
+	group := strings.TrimSuffix(pattern, ".*")
+	if strings.ContainsAny(group, ".*?[\\") {
+		return "", false
+	}
+	return group, true
+}
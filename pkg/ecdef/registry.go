@@ -0,0 +1,181 @@
+package ecdef
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Names of the error code groups defined in IGU library.
+// Each name matches the package that defines the codes of the group.
+const (
+	BASIC_GROUP = "ec"
+	HTTP_GROUP  = "echttp"
+	FS_GROUP    = "ecfs"
+	AUTH_GROUP  = "ecauth"
+	NET_GROUP   = "ecnet"
+	DB_GROUP    = "ecdb"
+	MATH_GROUP  = "ecmath"
+	SYS_GROUP   = "ecsys"
+	APP_GROUP   = "app"
+)
+
+// Group describes a named range of error codes
+// and the function that converts them to strings.
+type Group struct {
+	// Unique name of the group, e.g. "ecfs".
+	Name string
+	// The first error code of the range.
+	Begin ErrCode
+	// The last error code of the range (inclusive).
+	End ErrCode
+	// ToString converts an error code of this group to a string.
+	ToString func(code ErrCode) string
+	// CodeName returns the symbolic name of an error code of this group,
+	// e.g. "ecfs.NotADir", or an empty string if the code has no name (optional).
+	CodeName func(code ErrCode) string
+	// Codes returns all error codes defined in this group (optional),
+	// it is required to list the codes and to parse their names.
+	Codes func() []ErrCode
+}
+
+// Contains returns true if code belongs to the range of the group.
+func (g *Group) Contains(code ErrCode) bool {
+	return code >= g.Begin && code <= g.End
+}
+
+// registry is an immutable snapshot of all registered groups,
+// it is replaced as a whole on each registration so that lookups
+// need no locking.
+type registry struct {
+	// Groups sorted by Begin.
+	groups []Group
+	byName map[string]int
+}
+
+var registryMu sync.Mutex
+var currentRegistry atomic.Value
+
+func init() {
+	currentRegistry.Store(&registry{byName: map[string]int{}})
+}
+
+func loadRegistry() *registry {
+	return currentRegistry.Load().(*registry)
+}
+
+// RegisterGroup registers a named range of error codes along with
+// its string converter. It is intended to be called from an init() function
+// of the package that defines the codes.
+// Returns an error if the name is empty or already taken,
+// if the range is invalid or overlaps with a registered one,
+// or if g.ToString is nil.
+func RegisterGroup(g Group) error {
+	if g.Name == "" {
+		return fmt.Errorf("ecdef: group name must not be empty")
+	}
+	if g.Begin > g.End {
+		return fmt.Errorf("ecdef: group '%s' has invalid range [%d, %d]",
+			g.Name, g.Begin, g.End)
+	}
+	if g.ToString == nil {
+		return fmt.Errorf("ecdef: group '%s' has no ToString function", g.Name)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	old := loadRegistry()
+	if _, ok := old.byName[g.Name]; ok {
+		return fmt.Errorf("ecdef: group '%s' already registered", g.Name)
+	}
+	for _, other := range old.groups {
+		if g.Begin <= other.End && other.Begin <= g.End {
+			return fmt.Errorf("ecdef: range of group '%s' [%d, %d] overlaps "+
+				"with group '%s' [%d, %d]", g.Name, g.Begin, g.End,
+				other.Name, other.Begin, other.End)
+		}
+	}
+	groups := make([]Group, 0, len(old.groups)+1)
+	groups = append(groups, old.groups...)
+	groups = append(groups, g)
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Begin < groups[j].Begin })
+	byName := make(map[string]int, len(groups))
+	for i, gr := range groups {
+		byName[gr.Name] = i
+	}
+	currentRegistry.Store(&registry{groups: groups, byName: byName})
+	return nil
+}
+
+// MustRegisterGroup is same as RegisterGroup but panics in case of error.
+func MustRegisterGroup(g Group) {
+	if err := RegisterGroup(g); err != nil {
+		panic(err)
+	}
+}
+
+// GroupByName returns the registered group with specified name
+// and true, or an empty Group and false if there is no such group.
+func GroupByName(name string) (Group, bool) {
+	r := loadRegistry()
+	if i, ok := r.byName[name]; ok {
+		return r.groups[i], true
+	}
+	return Group{}, false
+}
+
+// GroupOf returns the registered group that contains code
+// and true, or an empty Group and false if code belongs to no group.
+func GroupOf(code ErrCode) (Group, bool) {
+	groups := loadRegistry().groups
+	i := sort.Search(len(groups), func(i int) bool { return groups[i].End >= code })
+	if i < len(groups) && groups[i].Contains(code) {
+		return groups[i], true
+	}
+	return Group{}, false
+}
+
+// InGroup returns true if code belongs to the registered group
+// with specified name.
+func InGroup(code ErrCode, name string) bool {
+	r := loadRegistry()
+	if i, ok := r.byName[name]; ok {
+		return r.groups[i].Contains(code)
+	}
+	return false
+}
+
+// Groups returns all registered groups sorted by range.
+func Groups() []Group {
+	groups := loadRegistry().groups
+	r := make([]Group, len(groups))
+	copy(r, groups)
+	return r
+}
+
+// CodeToString converts code to a string using the converter
+// of the group it belongs to.
+func CodeToString(code ErrCode) string {
+	if g, ok := GroupOf(code); ok {
+		return g.ToString(code)
+	}
+	return fmt.Sprintf("unknown error code (%d)", code)
+}
+
+// CodeName returns the symbolic name of code, e.g. "ec.NotFound".
+// If the group of the code does not provide a name for it,
+// a name composed of the group name and the numeric code is returned,
+// e.g. "ecfs(1000042)". If code belongs to no group,
+// an empty string is returned.
+func CodeName(code ErrCode) string {
+	g, ok := GroupOf(code)
+	if !ok {
+		return ""
+	}
+	if g.CodeName != nil {
+		if name := g.CodeName(code); name != "" {
+			return name
+		}
+	}
+	return fmt.Sprintf("%s(%d)", g.Name, code)
+}
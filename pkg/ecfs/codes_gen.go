@@ -0,0 +1,164 @@
+// Code generated by ecgen from codes.yaml. DO NOT EDIT.
+
+package ecfs
+
+import (
+	"fmt"
+
+	"github.com/iotanbo/igu/pkg/ecdef"
+)
+
+const (
+	// Error is a generic file system error that does not provide extra details.
+	Error ecdef.ErrCode = 1000000
+	// Entity is not a file.
+	NotAFile ecdef.ErrCode = 1000001
+	// Entity is not a directory.
+	NotADir ecdef.ErrCode = 1000002
+	// Entity is not a symlink.
+	NotASymlink ecdef.ErrCode = 1000003
+	// Entity is not a hardlink.
+	NotAHardlink ecdef.ErrCode = 1000004
+	// The file is corrupt.
+	FileCorrupt ecdef.ErrCode = 1000005
+	// The file is too large.
+	FileTooLarge ecdef.ErrCode = 1000006
+	// The path is invalid.
+	InvalidPath ecdef.ErrCode = 1000007
+	// No space left on the device.
+	NoSpace ecdef.ErrCode = 1000008
+	// Disk quota exceeded.
+	QuotaExceeded ecdef.ErrCode = 1000009
+	// Operation can't be performed across file systems (devices),
+	// e.g. renaming a file to another mount point.
+	CrossDevice ecdef.ErrCode = 1000010
+	// File name or path is too long.
+	NameTooLong ecdef.ErrCode = 1000011
+	// Directory is not empty.
+	DirNotEmpty ecdef.ErrCode = 1000012
+	// Too many levels of symbolic links (likely a symlink loop).
+	SymlinkLoop ecdef.ErrCode = 1000013
+	// Too many open files in the process or in the system.
+	TooManyOpenFiles ecdef.ErrCode = 1000014
+	// Too many hardlinks to the file.
+	TooManyLinks ecdef.ErrCode = 1000015
+	// File or device is busy (e.g. used by another process).
+	Busy ecdef.ErrCode = 1000016
+)
+
+func init() {
+	ecdef.MustRegisterGroup(ecdef.Group{
+		Name:     ecdef.FS_GROUP,
+		Begin:    ecdef.FS_RANGE_BEGIN,
+		End:      ecdef.FS_RANGE_END,
+		ToString: ECToString,
+		CodeName: ECName,
+		Codes:    ECCodes,
+	})
+}
+
+// ECToString(...) returns a string describing an ecfs error code.
+func ECToString(errCode ecdef.ErrCode) string {
+	switch errCode {
+	case Error:
+		return "file system error"
+	case NotAFile:
+		return "not a file"
+	case NotADir:
+		return "not a directory"
+	case NotASymlink:
+		return "not a symlink"
+	case NotAHardlink:
+		return "not a hardlink"
+	case FileCorrupt:
+		return "file is corrupt"
+	case FileTooLarge:
+		return "file is too large"
+	case InvalidPath:
+		return "invalid path"
+	case NoSpace:
+		return "no space left on device"
+	case QuotaExceeded:
+		return "disk quota exceeded"
+	case CrossDevice:
+		return "cross-device operation"
+	case NameTooLong:
+		return "file name too long"
+	case DirNotEmpty:
+		return "directory not empty"
+	case SymlinkLoop:
+		return "too many levels of symbolic links"
+	case TooManyOpenFiles:
+		return "too many open files"
+	case TooManyLinks:
+		return "too many links"
+	case Busy:
+		return "file or device busy"
+	}
+	return fmt.Sprintf("unknown ecfs error code (%d)", errCode)
+}
+
+// ECName(...) returns the symbolic name of an ecfs error code,
+// e.g. "ecfs.Error", or an empty string if the code is unknown.
+func ECName(errCode ecdef.ErrCode) string {
+	switch errCode {
+	case Error:
+		return "ecfs.Error"
+	case NotAFile:
+		return "ecfs.NotAFile"
+	case NotADir:
+		return "ecfs.NotADir"
+	case NotASymlink:
+		return "ecfs.NotASymlink"
+	case NotAHardlink:
+		return "ecfs.NotAHardlink"
+	case FileCorrupt:
+		return "ecfs.FileCorrupt"
+	case FileTooLarge:
+		return "ecfs.FileTooLarge"
+	case InvalidPath:
+		return "ecfs.InvalidPath"
+	case NoSpace:
+		return "ecfs.NoSpace"
+	case QuotaExceeded:
+		return "ecfs.QuotaExceeded"
+	case CrossDevice:
+		return "ecfs.CrossDevice"
+	case NameTooLong:
+		return "ecfs.NameTooLong"
+	case DirNotEmpty:
+		return "ecfs.DirNotEmpty"
+	case SymlinkLoop:
+		return "ecfs.SymlinkLoop"
+	case TooManyOpenFiles:
+		return "ecfs.TooManyOpenFiles"
+	case TooManyLinks:
+		return "ecfs.TooManyLinks"
+	case Busy:
+		return "ecfs.Busy"
+	}
+	return ""
+}
+
+// ECCodes(...) returns all ecfs error codes in the order of definition.
+func ECCodes() []ecdef.ErrCode {
+	return []ecdef.ErrCode{
+		Error,
+		NotAFile,
+		NotADir,
+		NotASymlink,
+		NotAHardlink,
+		FileCorrupt,
+		FileTooLarge,
+		InvalidPath,
+		NoSpace,
+		QuotaExceeded,
+		CrossDevice,
+		NameTooLong,
+		DirNotEmpty,
+		SymlinkLoop,
+		TooManyOpenFiles,
+		TooManyLinks,
+		Busy,
+	}
+}
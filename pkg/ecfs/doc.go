@@ -0,0 +1,7 @@
+// Package ecfs defines error codes of the file system error group
+// that are used in IGU library.
+//
+// Constants and converters are generated from codes.yaml by cmd/ecgen.
+package ecfs
+
+//go:generate go run github.com/iotanbo/igu/cmd/ecgen
@@ -0,0 +1,497 @@
+// Code generated by ecgen from codes.yaml. DO NOT EDIT.
+
+package echttp
+
+import (
+	"fmt"
+
+	"github.com/iotanbo/igu/pkg/ecdef"
+)
+
+const (
+	// Everything so far is OK and the client should continue the request,
+	// or ignore the response if the request is already finished.
+	Continue_100 ecdef.ErrCode = 100
+	// The server is switching to the protocol requested by the client
+	// in the Upgrade header.
+	SwitchingProtocols_101 ecdef.ErrCode = 101
+	// The server has received and is processing the request,
+	// but no response is available yet (WebDAV).
+	Processing_102 ecdef.ErrCode = 102
+	// Preliminary headers that let the client start preloading resources
+	// while the server prepares a response.
+	EarlyHints_103 ecdef.ErrCode = 103
+	// The request succeeded.
+	OK_200 ecdef.ErrCode = 200
+	// The request succeeded and a new resource was created.
+	Created_201 ecdef.ErrCode = 201
+	// The request has been received but not yet acted upon.
+	Accepted_202 ecdef.ErrCode = 202
+	// The returned metadata is not exactly the same as is available
+	// from the origin server, e.g. it was modified by a proxy.
+	NonAuthoritativeInfo_203 ecdef.ErrCode = 203
+	// There is no content to send for this request.
+	NoContent_204 ecdef.ErrCode = 204
+	// The client should reset the document that sent this request.
+	ResetContent_205 ecdef.ErrCode = 205
+	// Only part of the resource is sent, as requested by the Range header.
+	PartialContent_206 ecdef.ErrCode = 206
+	// The body contains statuses of multiple operations (WebDAV).
+	MultiStatus_207 ecdef.ErrCode = 207
+	// The members of a DAV binding have already been enumerated (WebDAV).
+	AlreadyReported_208 ecdef.ErrCode = 208
+	// The response is a result of instance-manipulations applied
+	// to the current instance.
+	IMUsed_226 ecdef.ErrCode = 226
+	// The request has more than one possible response.
+	MultipleChoices_300 ecdef.ErrCode = 300
+	// The URL of the requested resource has been changed permanently.
+	MovedPermanently_301 ecdef.ErrCode = 301
+	// The URL of the requested resource has been changed temporarily.
+	Found_302 ecdef.ErrCode = 302
+	// The client should get the requested resource at another URL with GET.
+	SeeOther_303 ecdef.ErrCode = 303
+	// The cached version of the resource is still valid.
+	NotModified_304 ecdef.ErrCode = 304
+	// The requested resource must be accessed through a proxy (deprecated).
+	UseProxy_305 ecdef.ErrCode = 305
+	// The requested resource is temporarily at another URL,
+	// the request method must not be changed.
+	TemporaryRedirect_307 ecdef.ErrCode = 307
+	// The requested resource is permanently at another URL,
+	// the request method must not be changed.
+	PermanentRedirect_308 ecdef.ErrCode = 308
+	// The server can't process the request due to a client error,
+	// e.g. malformed request syntax.
+	BadRequest_400 ecdef.ErrCode = 400
+	// The client must authenticate itself to get the requested response.
+	Unauthorized_401 ecdef.ErrCode = 401
+	// Reserved for future use, sometimes used by payment systems.
+	PaymentRequired_402 ecdef.ErrCode = 402
+	// The client is known but does not have access rights to the content.
+	Forbidden_403 ecdef.ErrCode = 403
+	// The server can't find the requested resource.
+	NotFound_404 ecdef.ErrCode = 404
+	// The request method is not supported by the target resource.
+	MethodNotAllowed_405 ecdef.ErrCode = 405
+	// No content matches the criteria given by the client
+	// in its Accept headers.
+	NotAcceptable_406 ecdef.ErrCode = 406
+	// The client must authenticate itself with a proxy.
+	ProxyAuthRequired_407 ecdef.ErrCode = 407
+	// The server timed out waiting for the request.
+	RequestTimeout_408 ecdef.ErrCode = 408
+	// The request conflicts with the current state of the server.
+	Conflict_409 ecdef.ErrCode = 409
+	// The requested resource has been permanently deleted from the server.
+	Gone_410 ecdef.ErrCode = 410
+	// The server requires the Content-Length header.
+	LengthRequired_411 ecdef.ErrCode = 411
+	// A precondition in the request headers is not met.
+	PreconditionFailed_412 ecdef.ErrCode = 412
+	// The request body is larger than the server is willing to process.
+	ContentTooLarge_413 ecdef.ErrCode = 413
+	// The requested URI is longer than the server is willing to interpret.
+	URITooLong_414 ecdef.ErrCode = 414
+	// The media format of the request body is not supported.
+	UnsupportedMediaType_415 ecdef.ErrCode = 415
+	// The range specified by the Range header can't be fulfilled.
+	RangeNotSatisfiable_416 ecdef.ErrCode = 416
+	// The expectation given in the Expect header can't be met.
+	ExpectationFailed_417 ecdef.ErrCode = 417
+	// The server refuses to brew coffee because it is a teapot (RFC 2324).
+	Teapot_418 ecdef.ErrCode = 418
+	// The request was directed at a server that can't produce a response.
+	MisdirectedRequest_421 ecdef.ErrCode = 421
+	// The request is well-formed but can't be processed
+	// due to semantic errors.
+	UnprocessableContent_422 ecdef.ErrCode = 422
+	// The resource being accessed is locked (WebDAV).
+	Locked_423 ecdef.ErrCode = 423
+	// The request failed because a previous request failed (WebDAV).
+	FailedDependency_424 ecdef.ErrCode = 424
+	// The server is unwilling to process a request that might be replayed.
+	TooEarly_425 ecdef.ErrCode = 425
+	// The client must switch to a different protocol.
+	UpgradeRequired_426 ecdef.ErrCode = 426
+	// The server requires the request to be conditional.
+	PreconditionRequired_428 ecdef.ErrCode = 428
+	// The client has sent too many requests in a given amount of time
+	// (rate limiting).
+	TooManyRequests_429 ecdef.ErrCode = 429
+	// The request header fields are too large.
+	RequestHeaderFieldsTooLarge_431 ecdef.ErrCode = 431
+	// The requested resource can't legally be provided,
+	// e.g. it is censored by a government.
+	UnavailableForLegalReasons_451 ecdef.ErrCode = 451
+	// The server has encountered a situation it does not know how to handle.
+	InternalServerError_500 ecdef.ErrCode = 500
+	// The request method is not supported by the server.
+	NotImplemented_501 ecdef.ErrCode = 501
+	// The server, while working as a gateway, got an invalid response
+	// from the upstream server.
+	BadGateway_502 ecdef.ErrCode = 502
+	// The server is not ready to handle the request,
+	// e.g. it is down for maintenance or overloaded.
+	ServiceUnavailable_503 ecdef.ErrCode = 503
+	// The server, while working as a gateway, did not get a response in time
+	// from the upstream server.
+	GatewayTimeout_504 ecdef.ErrCode = 504
+	// The HTTP version used in the request is not supported by the server.
+	HTTPVersionNotSupported_505 ecdef.ErrCode = 505
+	// The server has an internal configuration error:
+	// transparent content negotiation results in a circular reference.
+	VariantAlsoNegotiates_506 ecdef.ErrCode = 506
+	// The server is unable to store the representation needed
+	// to complete the request (WebDAV).
+	InsufficientStorage_507 ecdef.ErrCode = 507
+	// The server detected an infinite loop while processing the request (WebDAV).
+	LoopDetected_508 ecdef.ErrCode = 508
+	// Further extensions to the request are required for the server to fulfill it.
+	NotExtended_510 ecdef.ErrCode = 510
+	// The client needs to authenticate to gain network access,
+	// e.g. to a captive portal.
+	NetworkAuthRequired_511 ecdef.ErrCode = 511
+)
+
+func init() {
+	ecdef.MustRegisterGroup(ecdef.Group{
+		Name:     ecdef.HTTP_GROUP,
+		Begin:    ecdef.HTTP_RANGE_BEGIN,
+		End:      ecdef.HTTP_RANGE_END,
+		ToString: ECToString,
+		CodeName: ECName,
+		Codes:    ECCodes,
+	})
+}
+
+// ECToString(...) returns a string describing an echttp error code.
+func ECToString(errCode ecdef.ErrCode) string {
+	switch errCode {
+	case Continue_100:
+		return "100 continue"
+	case SwitchingProtocols_101:
+		return "101 switching protocols"
+	case Processing_102:
+		return "102 processing"
+	case EarlyHints_103:
+		return "103 early hints"
+	case OK_200:
+		return "200 OK"
+	case Created_201:
+		return "201 created"
+	case Accepted_202:
+		return "202 accepted"
+	case NonAuthoritativeInfo_203:
+		return "203 non-authoritative information"
+	case NoContent_204:
+		return "204 no content"
+	case ResetContent_205:
+		return "205 reset content"
+	case PartialContent_206:
+		return "206 partial content"
+	case MultiStatus_207:
+		return "207 multi-status"
+	case AlreadyReported_208:
+		return "208 already reported"
+	case IMUsed_226:
+		return "226 IM used"
+	case MultipleChoices_300:
+		return "300 multiple choices"
+	case MovedPermanently_301:
+		return "301 moved permanently"
+	case Found_302:
+		return "302 found"
+	case SeeOther_303:
+		return "303 see other"
+	case NotModified_304:
+		return "304 not modified"
+	case UseProxy_305:
+		return "305 use proxy"
+	case TemporaryRedirect_307:
+		return "307 temporary redirect"
+	case PermanentRedirect_308:
+		return "308 permanent redirect"
+	case BadRequest_400:
+		return "400 bad request"
+	case Unauthorized_401:
+		return "401 unauthorized"
+	case PaymentRequired_402:
+		return "402 payment required"
+	case Forbidden_403:
+		return "403 forbidden"
+	case NotFound_404:
+		return "404 not found"
+	case MethodNotAllowed_405:
+		return "405 method not allowed"
+	case NotAcceptable_406:
+		return "406 not acceptable"
+	case ProxyAuthRequired_407:
+		return "407 proxy authentication required"
+	case RequestTimeout_408:
+		return "408 request timeout"
+	case Conflict_409:
+		return "409 conflict"
+	case Gone_410:
+		return "410 gone"
+	case LengthRequired_411:
+		return "411 length required"
+	case PreconditionFailed_412:
+		return "412 precondition failed"
+	case ContentTooLarge_413:
+		return "413 content too large"
+	case URITooLong_414:
+		return "414 URI too long"
+	case UnsupportedMediaType_415:
+		return "415 unsupported media type"
+	case RangeNotSatisfiable_416:
+		return "416 range not satisfiable"
+	case ExpectationFailed_417:
+		return "417 expectation failed"
+	case Teapot_418:
+		return "418 I'm a teapot"
+	case MisdirectedRequest_421:
+		return "421 misdirected request"
+	case UnprocessableContent_422:
+		return "422 unprocessable content"
+	case Locked_423:
+		return "423 locked"
+	case FailedDependency_424:
+		return "424 failed dependency"
+	case TooEarly_425:
+		return "425 too early"
+	case UpgradeRequired_426:
+		return "426 upgrade required"
+	case PreconditionRequired_428:
+		return "428 precondition required"
+	case TooManyRequests_429:
+		return "429 too many requests"
+	case RequestHeaderFieldsTooLarge_431:
+		return "431 request header fields too large"
+	case UnavailableForLegalReasons_451:
+		return "451 unavailable for legal reasons"
+	case InternalServerError_500:
+		return "500 internal server error"
+	case NotImplemented_501:
+		return "501 not implemented"
+	case BadGateway_502:
+		return "502 bad gateway"
+	case ServiceUnavailable_503:
+		return "503 service unavailable"
+	case GatewayTimeout_504:
+		return "504 gateway timeout"
+	case HTTPVersionNotSupported_505:
+		return "505 HTTP version not supported"
+	case VariantAlsoNegotiates_506:
+		return "506 variant also negotiates"
+	case InsufficientStorage_507:
+		return "507 insufficient storage"
+	case LoopDetected_508:
+		return "508 loop detected"
+	case NotExtended_510:
+		return "510 not extended"
+	case NetworkAuthRequired_511:
+		return "511 network authentication required"
+	}
+	return fmt.Sprintf("HTTP status code %d", errCode)
+}
+
+// ECName(...) returns the symbolic name of an echttp error code,
+// e.g. "echttp.Continue_100", or an empty string if the code is unknown.
+func ECName(errCode ecdef.ErrCode) string {
+	switch errCode {
+	case Continue_100:
+		return "echttp.Continue_100"
+	case SwitchingProtocols_101:
+		return "echttp.SwitchingProtocols_101"
+	case Processing_102:
+		return "echttp.Processing_102"
+	case EarlyHints_103:
+		return "echttp.EarlyHints_103"
+	case OK_200:
+		return "echttp.OK_200"
+	case Created_201:
+		return "echttp.Created_201"
+	case Accepted_202:
+		return "echttp.Accepted_202"
+	case NonAuthoritativeInfo_203:
+		return "echttp.NonAuthoritativeInfo_203"
+	case NoContent_204:
+		return "echttp.NoContent_204"
+	case ResetContent_205:
+		return "echttp.ResetContent_205"
+	case PartialContent_206:
+		return "echttp.PartialContent_206"
+	case MultiStatus_207:
+		return "echttp.MultiStatus_207"
+	case AlreadyReported_208:
+		return "echttp.AlreadyReported_208"
+	case IMUsed_226:
+		return "echttp.IMUsed_226"
+	case MultipleChoices_300:
+		return "echttp.MultipleChoices_300"
+	case MovedPermanently_301:
+		return "echttp.MovedPermanently_301"
+	case Found_302:
+		return "echttp.Found_302"
+	case SeeOther_303:
+		return "echttp.SeeOther_303"
+	case NotModified_304:
+		return "echttp.NotModified_304"
+	case UseProxy_305:
+		return "echttp.UseProxy_305"
+	case TemporaryRedirect_307:
+		return "echttp.TemporaryRedirect_307"
+	case PermanentRedirect_308:
+		return "echttp.PermanentRedirect_308"
+	case BadRequest_400:
+		return "echttp.BadRequest_400"
+	case Unauthorized_401:
+		return "echttp.Unauthorized_401"
+	case PaymentRequired_402:
+		return "echttp.PaymentRequired_402"
+	case Forbidden_403:
+		return "echttp.Forbidden_403"
+	case NotFound_404:
+		return "echttp.NotFound_404"
+	case MethodNotAllowed_405:
+		return "echttp.MethodNotAllowed_405"
+	case NotAcceptable_406:
+		return "echttp.NotAcceptable_406"
+	case ProxyAuthRequired_407:
+		return "echttp.ProxyAuthRequired_407"
+	case RequestTimeout_408:
+		return "echttp.RequestTimeout_408"
+	case Conflict_409:
+		return "echttp.Conflict_409"
+	case Gone_410:
+		return "echttp.Gone_410"
+	case LengthRequired_411:
+		return "echttp.LengthRequired_411"
+	case PreconditionFailed_412:
+		return "echttp.PreconditionFailed_412"
+	case ContentTooLarge_413:
+		return "echttp.ContentTooLarge_413"
+	case URITooLong_414:
+		return "echttp.URITooLong_414"
+	case UnsupportedMediaType_415:
+		return "echttp.UnsupportedMediaType_415"
+	case RangeNotSatisfiable_416:
+		return "echttp.RangeNotSatisfiable_416"
+	case ExpectationFailed_417:
+		return "echttp.ExpectationFailed_417"
+	case Teapot_418:
+		return "echttp.Teapot_418"
+	case MisdirectedRequest_421:
+		return "echttp.MisdirectedRequest_421"
+	case UnprocessableContent_422:
+		return "echttp.UnprocessableContent_422"
+	case Locked_423:
+		return "echttp.Locked_423"
+	case FailedDependency_424:
+		return "echttp.FailedDependency_424"
+	case TooEarly_425:
+		return "echttp.TooEarly_425"
+	case UpgradeRequired_426:
+		return "echttp.UpgradeRequired_426"
+	case PreconditionRequired_428:
+		return "echttp.PreconditionRequired_428"
+	case TooManyRequests_429:
+		return "echttp.TooManyRequests_429"
+	case RequestHeaderFieldsTooLarge_431:
+		return "echttp.RequestHeaderFieldsTooLarge_431"
+	case UnavailableForLegalReasons_451:
+		return "echttp.UnavailableForLegalReasons_451"
+	case InternalServerError_500:
+		return "echttp.InternalServerError_500"
+	case NotImplemented_501:
+		return "echttp.NotImplemented_501"
+	case BadGateway_502:
+		return "echttp.BadGateway_502"
+	case ServiceUnavailable_503:
+		return "echttp.ServiceUnavailable_503"
+	case GatewayTimeout_504:
+		return "echttp.GatewayTimeout_504"
+	case HTTPVersionNotSupported_505:
+		return "echttp.HTTPVersionNotSupported_505"
+	case VariantAlsoNegotiates_506:
+		return "echttp.VariantAlsoNegotiates_506"
+	case InsufficientStorage_507:
+		return "echttp.InsufficientStorage_507"
+	case LoopDetected_508:
+		return "echttp.LoopDetected_508"
+	case NotExtended_510:
+		return "echttp.NotExtended_510"
+	case NetworkAuthRequired_511:
+		return "echttp.NetworkAuthRequired_511"
+	}
+	return ""
+}
+
+// ECCodes(...) returns all echttp error codes in the order of definition.
+func ECCodes() []ecdef.ErrCode {
+	return []ecdef.ErrCode{
+		Continue_100,
+		SwitchingProtocols_101,
+		Processing_102,
+		EarlyHints_103,
+		OK_200,
+		Created_201,
+		Accepted_202,
+		NonAuthoritativeInfo_203,
+		NoContent_204,
+		ResetContent_205,
+		PartialContent_206,
+		MultiStatus_207,
+		AlreadyReported_208,
+		IMUsed_226,
+		MultipleChoices_300,
+		MovedPermanently_301,
+		Found_302,
+		SeeOther_303,
+		NotModified_304,
+		UseProxy_305,
+		TemporaryRedirect_307,
+		PermanentRedirect_308,
+		BadRequest_400,
+		Unauthorized_401,
+		PaymentRequired_402,
+		Forbidden_403,
+		NotFound_404,
+		MethodNotAllowed_405,
+		NotAcceptable_406,
+		ProxyAuthRequired_407,
+		RequestTimeout_408,
+		Conflict_409,
+		Gone_410,
+		LengthRequired_411,
+		PreconditionFailed_412,
+		ContentTooLarge_413,
+		URITooLong_414,
+		UnsupportedMediaType_415,
+		RangeNotSatisfiable_416,
+		ExpectationFailed_417,
+		Teapot_418,
+		MisdirectedRequest_421,
+		UnprocessableContent_422,
+		Locked_423,
+		FailedDependency_424,
+		TooEarly_425,
+		UpgradeRequired_426,
+		PreconditionRequired_428,
+		TooManyRequests_429,
+		RequestHeaderFieldsTooLarge_431,
+		UnavailableForLegalReasons_451,
+		InternalServerError_500,
+		NotImplemented_501,
+		BadGateway_502,
+		ServiceUnavailable_503,
+		GatewayTimeout_504,
+		HTTPVersionNotSupported_505,
+		VariantAlsoNegotiates_506,
+		InsufficientStorage_507,
+		LoopDetected_508,
+		NotExtended_510,
+		NetworkAuthRequired_511,
+	}
+}
@@ -0,0 +1,8 @@
+// Package echttp defines status and error codes of the HTTP error group.
+// The value of every code is equal to the HTTP status code it represents,
+// so a status received from a server can be converted with ecdef.ErrCode(status).
+//
+// Constants and converters are generated from codes.yaml by cmd/ecgen.
+package echttp
+
+//go:generate go run github.com/iotanbo/igu/cmd/ecgen
@@ -0,0 +1,29 @@
+package echttp
+
+import "github.com/iotanbo/igu/pkg/ecdef"
+
+// IsInformational(...) checks that code is an informational (1xx) status.
+func IsInformational(code ecdef.ErrCode) bool { return code >= 100 && code <= 199 }
+
+// IsSuccess(...) checks that code is a successful (2xx) status.
+func IsSuccess(code ecdef.ErrCode) bool { return code >= 200 && code <= 299 }
+
+// IsRedirect(...) checks that code is a redirection (3xx) status.
+func IsRedirect(code ecdef.ErrCode) bool { return code >= 300 && code <= 399 }
+
+// IsClientError(...) checks that code is a client error (4xx) status.
+func IsClientError(code ecdef.ErrCode) bool { return code >= 400 && code <= 499 }
+
+// IsServerError(...) checks that code is a server error (5xx) status.
+func IsServerError(code ecdef.ErrCode) bool { return code >= 500 && code <= 599 }
+
+// IsRetryable(...) checks that a request that failed with status code
+// may succeed if repeated later:
+// TooManyRequests_429, BadGateway_502, ServiceUnavailable_503 and GatewayTimeout_504.
+func IsRetryable(code ecdef.ErrCode) bool {
+	switch code {
+	case TooManyRequests_429, BadGateway_502, ServiceUnavailable_503, GatewayTimeout_504:
+		return true
+	}
+	return false
+}
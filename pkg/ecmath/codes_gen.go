@@ -0,0 +1,72 @@
+// Code generated by ecgen from codes.yaml. DO NOT EDIT.
+
+package ecmath
+
+import (
+	"fmt"
+
+	"github.com/iotanbo/igu/pkg/ecdef"
+)
+
+const (
+	// Error is a generic math-related error that does not provide extra details.
+	Error ecdef.ErrCode = 1001200
+	// Floating point operation error.
+	FloatingPoint ecdef.ErrCode = 1001201
+	// Overflow error.
+	Overflow ecdef.ErrCode = 1001202
+	// Division by zero.
+	ZeroDivision ecdef.ErrCode = 1001203
+)
+
+func init() {
+	ecdef.MustRegisterGroup(ecdef.Group{
+		Name:     ecdef.MATH_GROUP,
+		Begin:    ecdef.MATH_RANGE_BEGIN,
+		End:      ecdef.MATH_RANGE_END,
+		ToString: ECToString,
+		CodeName: ECName,
+		Codes:    ECCodes,
+	})
+}
+
+// ECToString(...) returns a string describing an ecmath error code.
+func ECToString(errCode ecdef.ErrCode) string {
+	switch errCode {
+	case Error:
+		return "math error"
+	case FloatingPoint:
+		return "floating point error"
+	case Overflow:
+		return "overflow"
+	case ZeroDivision:
+		return "division by zero"
+	}
+	return fmt.Sprintf("unknown ecmath error code (%d)", errCode)
+}
+
+// ECName(...) returns the symbolic name of an ecmath error code,
+// e.g. "ecmath.Error", or an empty string if the code is unknown.
+func ECName(errCode ecdef.ErrCode) string {
+	switch errCode {
+	case Error:
+		return "ecmath.Error"
+	case FloatingPoint:
+		return "ecmath.FloatingPoint"
+	case Overflow:
+		return "ecmath.Overflow"
+	case ZeroDivision:
+		return "ecmath.ZeroDivision"
+	}
+	return ""
+}
+
+// ECCodes(...) returns all ecmath error codes in the order of definition.
+func ECCodes() []ecdef.ErrCode {
+	return []ecdef.ErrCode{
+		Error,
+		FloatingPoint,
+		Overflow,
+		ZeroDivision,
+	}
+}
@@ -0,0 +1,7 @@
+// Package ecmath defines error codes of the math error group
+// that are used in IGU library.
+//
+// Constants and converters are generated from codes.yaml by cmd/ecgen.
+package ecmath
+
+//go:generate go run github.com/iotanbo/igu/cmd/ecgen
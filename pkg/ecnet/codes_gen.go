@@ -0,0 +1,156 @@
+// Code generated by ecgen from codes.yaml. DO NOT EDIT.
+
+package ecnet
+
+import (
+	"fmt"
+
+	"github.com/iotanbo/igu/pkg/ecdef"
+)
+
+const (
+	// Error is a generic network-related error that does not provide extra details.
+	Error ecdef.ErrCode = 1000600
+	// Connection was refused by the remote server.
+	ConnectionRefused ecdef.ErrCode = 1000601
+	// Connection was reset by the remote server.
+	ConnectionReset ecdef.ErrCode = 1000602
+	// Connection was aborted (terminated) by the remote server.
+	ConnectionAborted ecdef.ErrCode = 1000603
+	// Network operation failed because it was not connected yet.
+	NotConnected ecdef.ErrCode = 1000604
+	// Socket address is already in use elsewhere.
+	AddrInUse ecdef.ErrCode = 1000605
+	// Nonexistent network interface was requested or the address is not local.
+	AddrNotAvailable ecdef.ErrCode = 1000606
+	// Remote host can't be reached.
+	HostUnreachable ecdef.ErrCode = 1000607
+	// Network can't be reached.
+	NetworkUnreachable ecdef.ErrCode = 1000608
+	// Network is down.
+	NetworkDown ecdef.ErrCode = 1000609
+	// Host name can't be resolved (DNS lookup failed).
+	DNSFailure ecdef.ErrCode = 1000610
+	// TLS handshake failed or the remote side sent a TLS alert.
+	TLSHandshake ecdef.ErrCode = 1000611
+	// Certificate is signed by an unknown (untrusted) authority.
+	UntrustedCertificate ecdef.ErrCode = 1000612
+	// Certificate is invalid, e.g. expired or not valid for the purpose.
+	InvalidCertificate ecdef.ErrCode = 1000613
+	// Certificate is not valid for the requested host name.
+	HostnameMismatch ecdef.ErrCode = 1000614
+	// Network address is malformed, e.g. the port is missing.
+	InvalidAddress ecdef.ErrCode = 1000615
+)
+
+func init() {
+	ecdef.MustRegisterGroup(ecdef.Group{
+		Name:     ecdef.NET_GROUP,
+		Begin:    ecdef.NET_RANGE_BEGIN,
+		End:      ecdef.NET_RANGE_END,
+		ToString: ECToString,
+		CodeName: ECName,
+		Codes:    ECCodes,
+	})
+}
+
+// ECToString(...) returns a string describing an ecnet error code.
+func ECToString(errCode ecdef.ErrCode) string {
+	switch errCode {
+	case Error:
+		return "network error"
+	case ConnectionRefused:
+		return "connection refused"
+	case ConnectionReset:
+		return "connection reset"
+	case ConnectionAborted:
+		return "connection aborted"
+	case NotConnected:
+		return "not connected"
+	case AddrInUse:
+		return "address in use"
+	case AddrNotAvailable:
+		return "address not available"
+	case HostUnreachable:
+		return "host unreachable"
+	case NetworkUnreachable:
+		return "network unreachable"
+	case NetworkDown:
+		return "network down"
+	case DNSFailure:
+		return "DNS lookup failed"
+	case TLSHandshake:
+		return "TLS handshake failed"
+	case UntrustedCertificate:
+		return "certificate signed by unknown authority"
+	case InvalidCertificate:
+		return "invalid certificate"
+	case HostnameMismatch:
+		return "certificate host name mismatch"
+	case InvalidAddress:
+		return "invalid network address"
+	}
+	return fmt.Sprintf("unknown ecnet error code (%d)", errCode)
+}
+
+// ECName(...) returns the symbolic name of an ecnet error code,
+// e.g. "ecnet.Error", or an empty string if the code is unknown.
+func ECName(errCode ecdef.ErrCode) string {
+	switch errCode {
+	case Error:
+		return "ecnet.Error"
+	case ConnectionRefused:
+		return "ecnet.ConnectionRefused"
+	case ConnectionReset:
+		return "ecnet.ConnectionReset"
+	case ConnectionAborted:
+		return "ecnet.ConnectionAborted"
+	case NotConnected:
+		return "ecnet.NotConnected"
+	case AddrInUse:
+		return "ecnet.AddrInUse"
+	case AddrNotAvailable:
+		return "ecnet.AddrNotAvailable"
+	case HostUnreachable:
+		return "ecnet.HostUnreachable"
+	case NetworkUnreachable:
+		return "ecnet.NetworkUnreachable"
+	case NetworkDown:
+		return "ecnet.NetworkDown"
+	case DNSFailure:
+		return "ecnet.DNSFailure"
+	case TLSHandshake:
+		return "ecnet.TLSHandshake"
+	case UntrustedCertificate:
+		return "ecnet.UntrustedCertificate"
+	case InvalidCertificate:
+		return "ecnet.InvalidCertificate"
+	case HostnameMismatch:
+		return "ecnet.HostnameMismatch"
+	case InvalidAddress:
+		return "ecnet.InvalidAddress"
+	}
+	return ""
+}
+
+// ECCodes(...) returns all ecnet error codes in the order of definition.
+func ECCodes() []ecdef.ErrCode {
+	return []ecdef.ErrCode{
+		Error,
+		ConnectionRefused,
+		ConnectionReset,
+		ConnectionAborted,
+		NotConnected,
+		AddrInUse,
+		AddrNotAvailable,
+		HostUnreachable,
+		NetworkUnreachable,
+		NetworkDown,
+		DNSFailure,
+		TLSHandshake,
+		UntrustedCertificate,
+		InvalidCertificate,
+		HostnameMismatch,
+		InvalidAddress,
+	}
+}
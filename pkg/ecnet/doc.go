@@ -0,0 +1,7 @@
+// Package ecnet defines error codes of the network error group
+// that are used in IGU library.
+//
+// Constants and converters are generated from codes.yaml by cmd/ecgen.
+package ecnet
+
+//go:generate go run github.com/iotanbo/igu/cmd/ecgen
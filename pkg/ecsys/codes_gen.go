@@ -0,0 +1,65 @@
+// Code generated by ecgen from codes.yaml. DO NOT EDIT.
+
+package ecsys
+
+import (
+	"fmt"
+
+	"github.com/iotanbo/igu/pkg/ecdef"
+)
+
+const (
+	// Error is a generic system error that does not provide extra details.
+	Error ecdef.ErrCode = 1001500
+	// System exit.
+	SystemExit ecdef.ErrCode = 1001501
+	// Interrupted from keyboard.
+	KeyboardInterrupt ecdef.ErrCode = 1001502
+)
+
+func init() {
+	ecdef.MustRegisterGroup(ecdef.Group{
+		Name:     ecdef.SYS_GROUP,
+		Begin:    ecdef.SYS_RANGE_BEGIN,
+		End:      ecdef.SYS_RANGE_END,
+		ToString: ECToString,
+		CodeName: ECName,
+		Codes:    ECCodes,
+	})
+}
+
+// ECToString(...) returns a string describing an ecsys error code.
+func ECToString(errCode ecdef.ErrCode) string {
+	switch errCode {
+	case Error:
+		return "system error"
+	case SystemExit:
+		return "system exit"
+	case KeyboardInterrupt:
+		return "keyboard interrupt"
+	}
+	return fmt.Sprintf("unknown ecsys error code (%d)", errCode)
+}
+
+// ECName(...) returns the symbolic name of an ecsys error code,
+// e.g. "ecsys.Error", or an empty string if the code is unknown.
+func ECName(errCode ecdef.ErrCode) string {
+	switch errCode {
+	case Error:
+		return "ecsys.Error"
+	case SystemExit:
+		return "ecsys.SystemExit"
+	case KeyboardInterrupt:
+		return "ecsys.KeyboardInterrupt"
+	}
+	return ""
+}
+
+// ECCodes(...) returns all ecsys error codes in the order of definition.
+func ECCodes() []ecdef.ErrCode {
+	return []ecdef.ErrCode{
+		Error,
+		SystemExit,
+		KeyboardInterrupt,
+	}
+}
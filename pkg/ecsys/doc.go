@@ -0,0 +1,7 @@
+// Package ecsys defines error codes of the system error group
+// that are used in IGU library.
+//
+// Constants and converters are generated from codes.yaml by cmd/ecgen.
+package ecsys
+
+//go:generate go run github.com/iotanbo/igu/cmd/ecgen
@@ -0,0 +1,29 @@
+/*
+Package errgrpc converts errs.Err to gRPC status and back.
+Features:
+  - IGU error codes are mapped to canonical gRPC codes
+    (ec.NotFound -> codes.NotFound, ec.TimedOut -> codes.DeadlineExceeded...),
+    see GRPCCode(); applications can add their own mappings
+    with RegisterCode() and RegisterGroupCode();
+  - the precise IGU code travels in an errdetails.ErrorInfo detail
+    of the status, so the client gets back an Err that Eq(), IsFS() etc.
+    work on; the message of the status is e.Msg, the context fields
+    and the cause chain are sent only if StatusOptions.FullChain is set;
+  - statuses of other servers are converted into Err by their gRPC code,
+    see ErrCode().
+
+Usage example:
+
+	// server
+	func (s *server) Get(ctx context.Context, r *pb.Request) (*pb.Reply, error) {
+		reply, e := s.get(ctx, r)
+		return reply, errgrpc.ToError(e)
+	}
+
+	// client
+	reply, err := client.Get(ctx, req)
+	if e := errgrpc.FromError(err); e.Some() {
+		if e.IsFS() { ... }
+	}
+*/
+package errgrpc
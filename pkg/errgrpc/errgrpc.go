@@ -0,0 +1,275 @@
+package errgrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecauth"
+	"github.com/iotanbo/igu/pkg/ecdef"
+	"github.com/iotanbo/igu/pkg/ecfs"
+	"github.com/iotanbo/igu/pkg/echttp"
+	"github.com/iotanbo/igu/pkg/ecmath"
+	"github.com/iotanbo/igu/pkg/errs"
+)
+
+// Domain of the errdetails.ErrorInfo that carries an IGU error.
+const DOMAIN = "github.com/iotanbo/igu"
+
+// Keys of the errdetails.ErrorInfo metadata.
+const (
+	// Numeric IGU error code.
+	KEY_CODE = "code"
+	// JSON form of the whole error chain (see errs.WireErr),
+	// attached only if StatusOptions.FullChain is set.
+	KEY_ERR = "err"
+)
+
+// Mappings of IGU error codes to gRPC codes.
+var mappingMu sync.RWMutex
+
+var codeTable = map[ecdef.ErrCode]codes.Code{
+	ec.NoError:                    codes.OK,
+	ec.NotFound:                   codes.NotFound,
+	ec.PermissionDenied:           codes.PermissionDenied,
+	ec.AlreadyExists:              codes.AlreadyExists,
+	ec.AlreadyClosed:              codes.FailedPrecondition,
+	ec.WouldBlock:                 codes.Unavailable,
+	ec.InvalidInput:               codes.InvalidArgument,
+	ec.InvalidData:                codes.InvalidArgument,
+	ec.Syntax:                     codes.InvalidArgument,
+	ec.Type:                       codes.InvalidArgument,
+	ec.Value:                      codes.InvalidArgument,
+	ec.Key:                        codes.InvalidArgument,
+	ec.Index:                      codes.OutOfRange,
+	ec.TimedOut:                   codes.DeadlineExceeded,
+	ec.DeadlineExceeded:           codes.DeadlineExceeded,
+	ec.Canceled:                   codes.Canceled,
+	ec.Interrupted:                codes.Canceled,
+	ec.UnexpectedEof:              codes.DataLoss,
+	ec.NotImplemented:             codes.Unimplemented,
+	ec.Unsupported:                codes.Unimplemented,
+	ec.Memory:                     codes.ResourceExhausted,
+	ec.NothingDone:                codes.FailedPrecondition,
+	ecfs.FileCorrupt:              codes.DataLoss,
+	ecfs.NoSpace:                  codes.ResourceExhausted,
+	ecfs.QuotaExceeded:            codes.ResourceExhausted,
+	ecfs.TooManyOpenFiles:         codes.ResourceExhausted,
+	ecfs.InvalidPath:              codes.InvalidArgument,
+	ecfs.NameTooLong:              codes.InvalidArgument,
+	ecfs.DirNotEmpty:              codes.FailedPrecondition,
+	ecfs.Busy:                     codes.Unavailable,
+	ecmath.Overflow:               codes.OutOfRange,
+	echttp.BadRequest_400:         codes.InvalidArgument,
+	echttp.Unauthorized_401:       codes.Unauthenticated,
+	echttp.Forbidden_403:          codes.PermissionDenied,
+	echttp.NotFound_404:           codes.NotFound,
+	echttp.Conflict_409:           codes.Aborted,
+	echttp.TooManyRequests_429:    codes.ResourceExhausted,
+	echttp.NotImplemented_501:     codes.Unimplemented,
+	echttp.ServiceUnavailable_503: codes.Unavailable,
+	echttp.GatewayTimeout_504:     codes.DeadlineExceeded,
+	ecauth.Failed:                 codes.Unauthenticated,
+	ecauth.Credentials:            codes.Unauthenticated,
+	ecauth.UnsupportedMethod:      codes.Unauthenticated,
+}
+
+var groupTable = map[string]codes.Code{
+	ecdef.NET_GROUP:  codes.Unavailable,
+	ecdef.AUTH_GROUP: codes.Unauthenticated,
+}
+
+// IGU error codes that correspond to gRPC codes of statuses
+// that do not carry an IGU error; only generic codes are used here.
+var reverseTable = map[codes.Code]ecdef.ErrCode{
+	codes.OK:                 ec.NoError,
+	codes.Canceled:           ec.Canceled,
+	codes.Unknown:            ec.Other,
+	codes.InvalidArgument:    ec.InvalidInput,
+	codes.DeadlineExceeded:   ec.DeadlineExceeded,
+	codes.NotFound:           ec.NotFound,
+	codes.AlreadyExists:      ec.AlreadyExists,
+	codes.PermissionDenied:   ec.PermissionDenied,
+	codes.ResourceExhausted:  ec.WouldBlock,
+	codes.FailedPrecondition: ec.Other,
+	codes.Aborted:            ec.Interrupted,
+	codes.OutOfRange:         ec.Index,
+	codes.Unimplemented:      ec.NotImplemented,
+	codes.Internal:           ec.Other,
+	codes.Unavailable:        ec.WouldBlock,
+	codes.DataLoss:           ec.InvalidData,
+	codes.Unauthenticated:    ecauth.Failed,
+}
+
+// RegisterCode sets the gRPC code of an IGU error code,
+// replacing the default mapping if any.
+func RegisterCode(code ecdef.ErrCode, grpcCode codes.Code) {
+	mappingMu.Lock()
+	defer mappingMu.Unlock()
+	codeTable[code] = grpcCode
+}
+
+// RegisterGroupCode sets the gRPC code of all error codes of the group
+// with specified name that have no mapping of their own.
+func RegisterGroupCode(group string, grpcCode codes.Code) {
+	mappingMu.Lock()
+	defer mappingMu.Unlock()
+	groupTable[group] = grpcCode
+}
+
+// GRPCCode returns the gRPC code that corresponds to an IGU error code:
+// the mapping of the code itself, then the mapping of its group,
+// then codes.Unknown.
+func GRPCCode(code ecdef.ErrCode) codes.Code {
+	mappingMu.RLock()
+	defer mappingMu.RUnlock()
+	if c, ok := codeTable[code]; ok {
+		return c
+	}
+	if g, ok := ecdef.GroupOf(code); ok {
+		if c, ok := groupTable[g.Name]; ok {
+			return c
+		}
+	}
+	return codes.Unknown
+}
+
+// ErrCode returns the IGU error code that corresponds to a gRPC code,
+// it is used for statuses that do not carry an IGU error.
+// Returns ec.Other for unknown codes.
+func ErrCode(grpcCode codes.Code) ecdef.ErrCode {
+	if code, ok := reverseTable[grpcCode]; ok {
+		return code
+	}
+	return ec.Other
+}
+
+// StatusOptions specify how a gRPC status is created from an error.
+type StatusOptions struct {
+	// If true, the whole chain of the error is attached to the status
+	// (see KEY_ERR), so FromStatus() restores it on the client side.
+	// It may contain OS error text, file paths and other internals,
+	// so enable it only if the client is trusted.
+	FullChain bool
+}
+
+// ToStatus converts e into a gRPC status with the code returned
+// by GRPCCode() and e.Msg as message (the description of e.Code
+// if e.Msg is empty). The IGU error code is attached as
+// an errdetails.ErrorInfo detail (see FromStatus()) whose reason
+// is the name of e.Code in UPPER_SNAKE_CASE, e.g. "NOT_FOUND" for ec.NotFound;
+// the cause chain is attached only if StatusOptions.FullChain is set.
+// Returns a status with codes.OK if e has no error.
+func ToStatus(e errs.Err, options ...StatusOptions) *status.Status {
+	if e.None() {
+		return status.New(codes.OK, "")
+	}
+	var o StatusOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	msg := e.Msg
+	if msg == "" {
+		msg = ecdef.CodeToString(e.Code)
+	}
+	s := status.New(GRPCCode(e.Code), msg)
+	info := &errdetails.ErrorInfo{
+		Reason: reason(e.Code),
+		Domain: DOMAIN,
+		Metadata: map[string]string{
+			KEY_CODE: strconv.FormatInt(int64(e.Code), 10),
+		},
+	}
+	if o.FullChain {
+		if data, err := json.Marshal(e); err == nil {
+			info.Metadata[KEY_ERR] = string(data)
+		}
+	}
+	if withDetails, err := s.WithDetails(info); err == nil {
+		return withDetails
+	}
+	return s
+}
+
+// reason converts the name of an error code (e.g. "ecfs.NotADir")
+// into an errdetails.ErrorInfo reason (e.g. "NOT_A_DIR").
+// Codes without a name get a reason like "CODE_1000042".
+func reason(code ecdef.ErrCode) string {
+	name := ecdef.CodeName(code)
+	if name == "" || strings.ContainsRune(name, '(') {
+		return fmt.Sprintf("CODE_%d", code)
+	}
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := rune(name[i-1])
+			nextLower := i+1 < len(name) && unicode.IsLower(rune(name[i+1]))
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// ToError is same as ToStatus() but returns the status as error
+// to be returned from a gRPC handler, or nil if e has no error.
+func ToError(e errs.Err, options ...StatusOptions) error {
+	if e.None() {
+		return nil
+	}
+	return ToStatus(e, options...).Err()
+}
+
+// FromStatus converts a gRPC status into Err.
+// If the status carries an IGU error code (see ToStatus()), errs.Err
+// has this code and the status message as Msg; the original error chain
+// is restored if it was attached with StatusOptions.FullChain, so that
+// Eq(), IsFS(), Field() etc. work as on the server side. Otherwise
+// errs.Err has the code returned by ErrCode() and the status message as Msg.
+// Returns errs.NoError if s is nil or has codes.OK.
+func FromStatus(s *status.Status) errs.Err {
+	if s == nil || s.Code() == codes.OK {
+		return errs.NoError
+	}
+	for _, detail := range s.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.Domain != DOMAIN {
+			continue
+		}
+		var e errs.Err
+		if err := json.Unmarshal([]byte(info.Metadata[KEY_ERR]), &e); err == nil {
+			return e
+		}
+		if n, err := strconv.ParseInt(info.Metadata[KEY_CODE], 10, 32); err == nil {
+			return errs.Err{Code: ecdef.ErrCode(n), Msg: s.Message()}
+		}
+	}
+	return errs.Err{Code: ErrCode(s.Code()), Msg: s.Message()}
+}
+
+// FromError converts an error returned by a gRPC call into Err,
+// see FromStatus(). Errors that are not gRPC statuses are converted
+// with errs.FromError(). Returns errs.NoError if err is nil.
+func FromError(err error) errs.Err {
+	if err == nil {
+		return errs.NoError
+	}
+	if s, ok := status.FromError(err); ok {
+		return FromStatus(s)
+	}
+	return errs.FromError(err)
+}
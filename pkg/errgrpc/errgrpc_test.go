@@ -0,0 +1,145 @@
+package errgrpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecdef"
+	"github.com/iotanbo/igu/pkg/ecfs"
+	"github.com/iotanbo/igu/pkg/echttp"
+	"github.com/iotanbo/igu/pkg/ecnet"
+	"github.com/iotanbo/igu/pkg/errs"
+)
+
+// transfer simulates sending s over the wire.
+func transfer(t *testing.T, s *status.Status) error {
+	data, err := proto.Marshal(s.Proto())
+	require.Nil(t, err)
+	var received spb.Status
+	require.Nil(t, proto.Unmarshal(data, &received))
+	return status.FromProto(&received).Err()
+}
+
+func TestRoundTrip(t *testing.T) {
+	sent := errs.Err{Code: ecfs.NotADir, Msg: "config",
+		Cause: errors.New("raw")}.With(errs.Path("/etc/app"))
+	s := ToStatus(sent, StatusOptions{FullChain: true})
+	require.Equal(t, codes.Unknown, s.Code())
+	require.Equal(t, "config", s.Message())
+	info := s.Details()[0].(*errdetails.ErrorInfo)
+	require.Equal(t, "NOT_A_DIR", info.Reason)
+	require.Equal(t, DOMAIN, info.Domain)
+
+	e := FromError(transfer(t, s))
+	require.True(t, e.Eq(ecfs.NotADir))
+	require.True(t, e.IsFS())
+	require.Equal(t, "config", e.Msg)
+	path, _ := e.Field(errs.KeyPath)
+	require.Equal(t, "/etc/app", path)
+	require.Equal(t, sent.Error(), e.Error())
+
+	// By default only the code and the message are sent
+	s = ToStatus(sent)
+	require.Equal(t, "config", s.Message())
+	info = s.Details()[0].(*errdetails.ErrorInfo)
+	require.NotContains(t, info.Metadata, KEY_ERR)
+	e = FromError(transfer(t, s))
+	require.True(t, e.Eq(ecfs.NotADir))
+	require.Equal(t, "config", e.Msg)
+	_, ok := e.Field(errs.KeyPath)
+	require.False(t, ok)
+	require.Nil(t, e.Cause)
+
+	s = ToStatus(errs.Err{Code: ec.NotFound})
+	require.Equal(t, "ec.NotFound", s.Message())
+	e = FromError(transfer(t, s))
+	require.True(t, e.Eq(ec.NotFound))
+
+	require.Nil(t, ToError(errs.NoError))
+	require.Equal(t, codes.OK, ToStatus(errs.NoError).Code())
+	require.Equal(t, errs.NoError, FromError(nil))
+	require.Equal(t, errs.NoError, FromStatus(nil))
+}
+
+func TestForeignStatus(t *testing.T) {
+	e := FromError(status.Error(codes.NotFound, "no such user"))
+	require.True(t, e.Eq(ec.NotFound))
+	require.Equal(t, "no such user", e.Msg)
+
+	e = FromError(status.Error(codes.Code(100), "unknown"))
+	require.True(t, e.Eq(ec.Other))
+
+	// Errors that are not statuses are converted with errs.FromError()
+	e = FromError(errors.New("plain"))
+	require.True(t, e.Eq(ec.Other))
+}
+
+func TestGRPCCode(t *testing.T) {
+	var testData = []struct {
+		code     ecdef.ErrCode
+		grpcCode codes.Code
+	}{
+		{ec.NoError, codes.OK},
+		{ec.NotFound, codes.NotFound},
+		{ec.PermissionDenied, codes.PermissionDenied},
+		{ec.AlreadyExists, codes.AlreadyExists},
+		{ec.TimedOut, codes.DeadlineExceeded},
+		{ec.DeadlineExceeded, codes.DeadlineExceeded},
+		{ec.Canceled, codes.Canceled},
+		{ec.NotImplemented, codes.Unimplemented},
+		{ec.InvalidInput, codes.InvalidArgument},
+		{ecnet.ConnectionRefused, codes.Unavailable},
+		{ec.Dummy, codes.Unknown},
+	}
+	for _, td := range testData {
+		require.Equal(t, td.grpcCode, GRPCCode(td.code), ecdef.CodeName(td.code))
+	}
+
+	RegisterCode(ec.Dummy, codes.Internal)
+	require.Equal(t, codes.Internal, GRPCCode(ec.Dummy))
+	RegisterGroupCode(ecdef.FS_GROUP, codes.Internal)
+	require.Equal(t, codes.Internal, GRPCCode(ecfs.SymlinkLoop))
+	require.Equal(t, codes.ResourceExhausted, GRPCCode(ecfs.NoSpace))
+}
+
+func TestErrCode(t *testing.T) {
+	var testData = []struct {
+		grpcCode codes.Code
+		code     ecdef.ErrCode
+	}{
+		{codes.NotFound, ec.NotFound},
+		{codes.FailedPrecondition, ec.Other},
+		{codes.ResourceExhausted, ec.WouldBlock},
+		{codes.Unavailable, ec.WouldBlock},
+		{codes.Aborted, ec.Interrupted},
+		{codes.Code(100), ec.Other},
+	}
+	for _, td := range testData {
+		require.Equal(t, td.code, ErrCode(td.grpcCode), td.grpcCode.String())
+	}
+}
+
+func TestReason(t *testing.T) {
+	var testData = []struct {
+		code   ecdef.ErrCode
+		reason string
+	}{
+		{ec.NotFound, "NOT_FOUND"},
+		{ecfs.NotADir, "NOT_A_DIR"},
+		{ec.UnexpectedEof, "UNEXPECTED_EOF"},
+		{echttp.NotFound_404, "NOT_FOUND_404"},
+		{ecnet.DNSFailure, "DNS_FAILURE"},
+		{ecdef.ErrCode(-42), "CODE_-42"},
+	}
+	for _, td := range testData {
+		require.Equal(t, td.reason, reason(td.code))
+	}
+}
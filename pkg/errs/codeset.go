@@ -0,0 +1,229 @@
+package errs
+
+import (
+	"sort"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecdef"
+	"github.com/iotanbo/igu/pkg/ecfs"
+	"github.com/iotanbo/igu/pkg/echttp"
+	"github.com/iotanbo/igu/pkg/ecmath"
+	"github.com/iotanbo/igu/pkg/ecnet"
+)
+
+// CodeRange is an inclusive range of error codes.
+type CodeRange struct {
+	Begin ecdef.ErrCode
+	End   ecdef.ErrCode
+}
+
+// CodeSet is an immutable set of error codes built from single codes,
+// ranges and other sets. Methods that add codes return a new set,
+// so predefined sets can be extended safely:
+//
+//	var Retryable = Transient.With(app.ErrLocked).WithRange(ecdef.DB_RANGE_BEGIN, ecdef.DB_RANGE_END)
+//
+// The zero value is an empty set.
+type CodeSet struct {
+	codes  map[ecdef.ErrCode]struct{}
+	ranges []CodeRange
+}
+
+// NewCodeSet creates a set of specified codes.
+func NewCodeSet(codes ...ecdef.ErrCode) CodeSet {
+	return CodeSet{}.With(codes...)
+}
+
+// With returns a new set that contains the codes of s and specified codes.
+func (s CodeSet) With(codes ...ecdef.ErrCode) CodeSet {
+	r := s.clone(len(codes))
+	for _, code := range codes {
+		r.codes[code] = struct{}{}
+	}
+	return r
+}
+
+// WithRange returns a new set that contains the codes of s
+// and all codes from begin to end inclusively.
+// An empty range (end < begin) adds nothing.
+func (s CodeSet) WithRange(begin, end ecdef.ErrCode) CodeSet {
+	r := s.clone(0)
+	if begin <= end {
+		r.ranges = append(r.ranges, CodeRange{Begin: begin, End: end})
+	}
+	return r
+}
+
+// WithGroup returns a new set that contains the codes of s and the range
+// of the registered group with specified name (e.g. ecdef.NET_GROUP).
+// The set is not changed if there is no such group.
+func (s CodeSet) WithGroup(name string) CodeSet {
+	g, ok := ecdef.GroupByName(name)
+	if !ok {
+		return s
+	}
+	return s.WithRange(g.Begin, g.End)
+}
+
+// Union returns a new set that contains the codes of s and of all others.
+func (s CodeSet) Union(others ...CodeSet) CodeSet {
+	r := s.clone(0)
+	for _, o := range others {
+		for code := range o.codes {
+			r.codes[code] = struct{}{}
+		}
+		r.ranges = append(r.ranges, o.ranges...)
+	}
+	return r
+}
+
+func (s CodeSet) clone(extra int) CodeSet {
+	r := CodeSet{
+		codes:  make(map[ecdef.ErrCode]struct{}, len(s.codes)+extra),
+		ranges: make([]CodeRange, len(s.ranges)),
+	}
+	for code := range s.codes {
+		r.codes[code] = struct{}{}
+	}
+	copy(r.ranges, s.ranges)
+	return r
+}
+
+// Contains returns true if code belongs to the set.
+func (s CodeSet) Contains(code ecdef.ErrCode) bool {
+	if _, ok := s.codes[code]; ok {
+		return true
+	}
+	for _, r := range s.ranges {
+		if code >= r.Begin && code <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// Codes returns the single codes of the set in ascending order,
+// the ranges are not expanded, see Ranges().
+func (s CodeSet) Codes() []ecdef.ErrCode {
+	r := make([]ecdef.ErrCode, 0, len(s.codes))
+	for code := range s.codes {
+		r = append(r, code)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i] < r[j] })
+	return r
+}
+
+// Ranges returns the ranges of the set in order of addition.
+func (s CodeSet) Ranges() []CodeRange {
+	r := make([]CodeRange, len(s.ranges))
+	copy(r, s.ranges)
+	return r
+}
+
+// Predefined sets for common policy checks.
+var (
+	// Transient errors may disappear if the operation is repeated later:
+	// timeouts and exceeded deadlines, interrupted and would-block calls,
+	// refused or broken connections, busy resources,
+	// HTTP 408, 429, 502, 503 and 504.
+	Transient = NewCodeSet(
+		ec.TimedOut,
+		ec.DeadlineExceeded,
+		ec.Interrupted,
+		ec.WouldBlock,
+		ecfs.Busy,
+		ecfs.TooManyOpenFiles,
+		ecnet.ConnectionRefused,
+		ecnet.ConnectionReset,
+		ecnet.ConnectionAborted,
+		ecnet.NotConnected,
+		ecnet.HostUnreachable,
+		ecnet.NetworkUnreachable,
+		ecnet.NetworkDown,
+		echttp.RequestTimeout_408,
+		echttp.TooManyRequests_429,
+		echttp.BadGateway_502,
+		echttp.ServiceUnavailable_503,
+		echttp.GatewayTimeout_504,
+	)
+
+	// NotFoundLike errors report that a requested resource does not exist.
+	NotFoundLike = NewCodeSet(
+		ec.NotFound,
+		echttp.NotFound_404,
+		echttp.Gone_410,
+	)
+
+	// PermissionLike errors report missing permissions or credentials,
+	// including all codes of the ecauth group.
+	PermissionLike = NewCodeSet(
+		ec.PermissionDenied,
+		echttp.Unauthorized_401,
+		echttp.Forbidden_403,
+		echttp.ProxyAuthRequired_407,
+	).WithRange(ecdef.AUTH_RANGE_BEGIN, ecdef.AUTH_RANGE_END)
+
+	// CallerBug errors are caused by invalid use of an API
+	// or by a bug of the program, retrying won't help.
+	CallerBug = NewCodeSet(
+		ec.InvalidInput,
+		ec.Assertion,
+		ec.Index,
+		ec.Type,
+		ec.Value,
+		ec.Panic,
+		ecfs.InvalidPath,
+		ecmath.ZeroDivision,
+		echttp.BadRequest_400,
+		echttp.MethodNotAllowed_405,
+		echttp.UnprocessableContent_422,
+	)
+)
+
+// InSet() returns true if e or any error in its Cause chain
+// (including the errors collected by MultiErr) has a code from s:
+//
+//	if e.InSet(Transient) { /* retry later */ }
+func (e *Err) InSet(s CodeSet) bool {
+	_, ok := e.FindInSet(s)
+	return ok
+}
+
+// FindInSet() returns the first error of the chain of e
+// that has a code from s and true, or NoError and false, see InSet().
+func (e *Err) FindInSet(s CodeSet) (Err, bool) {
+	var found Err
+	ok := walkErrs(*e, func(current Err) bool {
+		if s.Contains(current.Code) {
+			found = current
+			return true
+		}
+		return false
+	})
+	return found, ok
+}
+
+// walkErrs calls f for each Err of the chain of err (depth-first,
+// following both Unwrap() error and Unwrap() []error)
+// until f returns true. Returns true if f returned true.
+func walkErrs(err error, f func(Err) bool) bool {
+	for err != nil {
+		if e, ok := AsErr(err); ok && f(e) {
+			return true
+		}
+		switch x := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, inner := range x.Unwrap() {
+				if walkErrs(inner, f) {
+					return true
+				}
+			}
+			return false
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		default:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package errs
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecdef"
+)
+
+// ContextErrorCode returns ec.DeadlineExceeded if context.DeadlineExceeded
+// is found anywhere in the chain of err, ec.Canceled if context.Canceled
+// is found, or ec.Other and false otherwise.
+func ContextErrorCode(err error) (ecdef.ErrCode, bool) {
+	if err == nil {
+		return ec.Other, false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ec.DeadlineExceeded, true
+	}
+	if errors.Is(err, context.Canceled) {
+		return ec.Canceled, true
+	}
+	return ec.Other, false
+}
+
+// FromContext returns the Err that corresponds to ctx.Err():
+// NoError if ctx is not done, otherwise ec.Canceled or ec.DeadlineExceeded
+// with ctx.Err() as Cause. Convenient for checks in long-running loops:
+//
+//	for _, item := range items {
+//		if e := FromContext(ctx); e.Some() {
+//			return e
+//		}
+//		...
+//	}
+func FromContext(ctx context.Context) Err {
+	err := ctx.Err()
+	if err == nil {
+		return NoError
+	}
+	code, _ := ContextErrorCode(err)
+	return Err{Code: code, Cause: err}
+}
@@ -16,4 +16,44 @@
 //   * error wrapping supported;
 //   * standard error interface supported;
 //   * support for custom, application-unique errors specified by user;
+//   * third-party libraries can register their own error code groups
+//     with ecdef.RegisterGroup(), these work the same way as built-in ones;
+//   * opt-in recording of the call stack, see SetStackCapture() and
+//     Err.WithStack(); use "%+v" to print the whole chain with stack frames;
+//   * ordered key/value context fields (path, op, uid, url...)
+//     attached with Err.With() and read back with Err.Field();
+//   * JSON serialization of whole error chains with a stable schema,
+//     see WireErr;
+//   * FromError() classifies standard errors by errno rather than by
+//     their (possibly localized) text, applications can add their own
+//     mappings with RegisterErrorMapper() and RegisterErrno();
+//   * network errors (DNS, TLS, certificates, timeouts) are mapped
+//     into ecnet codes, see NetErrorCode();
+//   * error code packages are generated from declarative specs
+//     by cmd/ecgen, applications can generate their APP-range codes
+//     the same way and assign AppECToString, AppECName and AppECCodes;
+//   * every code has a symbolic name (e.g. "ecnet.ConnectionRefused"),
+//     see Err.Name(); names can be parsed back and matched against
+//     patterns like "ecnet.*" with ecdef.ParseCodeName() and ecdef.MatchCodes();
+//   * errors of batch operations are collected with MultiErr,
+//     which converts to Err with ToErr() to fit the Some()/None() idiom;
+//   * errors.Is() matches codes anywhere in the chain, e.g. errors.Is(err, Code(ec.NotFound)),
+//     and typed causes are extracted with CauseAs[T]();
+//   * errors are mapped to HTTP status codes and process exit codes
+//     with configurable tables, see Err.HTTPStatus() and Err.ExitCode();
+//   * descriptions of error codes are localized with message catalogs
+//     (see RegisterMessages() and LoadMessages()) and whole chains are
+//     rendered in a given language with Err.LocalizedError();
+//   * errors are logged as groups of structured attributes (code, name,
+//     fields, causes...) with log/slog, see package errslog;
+//   * panics are converted into errors with Recover() and Go();
+//   * immutable code sets (CodeSet) classify errors for policy checks,
+//     e.g. e.InSet(Transient); NotFoundLike, PermissionLike and CallerBug
+//     are predefined as well;
+//   * cancellation and deadlines of context.Context are reported
+//     as ec.Canceled and ec.DeadlineExceeded, see FromContext();
+//   * errors are sent over gRPC with their codes, messages and causes,
+//     see package errgrpc;
+//   * tests check error codes, chains and fields with the assertions
+//     of package errstest;
 package errs
@@ -72,5 +72,5 @@ func Example() {
 
 	// Output:
 	// Got dummy error (as expected).
-	// my app error: dummy error for testing purposes
+	// my app error: ec.Dummy (dummy error for testing purpose) for testing purposes
 }
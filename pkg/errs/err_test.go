@@ -1,16 +1,30 @@
 package errs
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
 	"log"
+	"net"
+	"net/url"
 	"os"
+	"os/exec"
+	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/iotanbo/igu/pkg/ec"
 
+	"github.com/iotanbo/igu/pkg/ecauth"
 	"github.com/iotanbo/igu/pkg/ecdef"
+	"github.com/iotanbo/igu/pkg/ecfs"
+	"github.com/iotanbo/igu/pkg/echttp"
+	"github.com/iotanbo/igu/pkg/ecmath"
+	"github.com/iotanbo/igu/pkg/ecnet"
 	"github.com/stretchr/testify/require"
 )
 
@@ -26,7 +40,7 @@ func ExampleAsErr() {
 		fmt.Println("Second err is not of type Err.")
 	}
 	// Output:
-	// dummy error
+	// ec.Dummy (dummy error for testing purpose)
 	// Second err is not of type Err.
 }
 
@@ -156,6 +170,202 @@ func TestECRanges(t *testing.T) {
 
 }
 
+// Codes of a third-party group used to test the group registry.
+const (
+	testGroupBegin ecdef.ErrCode = 2000000000
+	testGroupEnd   ecdef.ErrCode = 2000000099
+	testGroupError ecdef.ErrCode = testGroupBegin
+)
+
+func TestRegisterGroup(t *testing.T) {
+	err := ecdef.RegisterGroup(ecdef.Group{
+		Name:  "testgroup",
+		Begin: testGroupBegin,
+		End:   testGroupEnd,
+		ToString: func(code ecdef.ErrCode) string {
+			if code == testGroupError {
+				return "test group error"
+			}
+			return fmt.Sprintf("unknown testgroup error code (%d)", code)
+		},
+	})
+	require.Nil(t, err, "registering a unique group must succeed: %v", err)
+
+	// Third-party group works the same way as built-in ones
+	e := Err{Code: testGroupError, Msg: "details"}
+	require.True(t, e.InGroup("testgroup"), "e.InGroup('testgroup') must return true")
+	require.False(t, e.IsApp(), "e.IsApp() must return false")
+	require.Equal(t, "testgroup", e.Group())
+	require.Equal(t, "test group error details", e.Error())
+
+	// Overlapping ranges must be rejected
+	err = ecdef.RegisterGroup(ecdef.Group{
+		Name:     "overlapping",
+		Begin:    testGroupEnd,
+		End:      testGroupEnd + 100,
+		ToString: func(code ecdef.ErrCode) string { return "" },
+	})
+	require.NotNil(t, err, "registering an overlapping group must fail")
+	err = ecdef.RegisterGroup(ecdef.Group{
+		Name:     "overlapping_builtin",
+		Begin:    ecdef.FS_RANGE_END,
+		End:      ecdef.FS_RANGE_END + 1,
+		ToString: func(code ecdef.ErrCode) string { return "" },
+	})
+	require.NotNil(t, err, "registering a group overlapping ecfs must fail")
+
+	// Duplicate names must be rejected
+	err = ecdef.RegisterGroup(ecdef.Group{
+		Name:     ecdef.FS_GROUP,
+		Begin:    testGroupEnd + 1000,
+		End:      testGroupEnd + 1100,
+		ToString: func(code ecdef.ErrCode) string { return "" },
+	})
+	require.NotNil(t, err, "registering a duplicate group name must fail")
+
+	require.Panics(t, func() {
+		ecdef.MustRegisterGroup(ecdef.Group{
+			Name:     "overlapping_basic",
+			Begin:    ecdef.BASIC_RANGE_BEGIN,
+			End:      ecdef.BASIC_RANGE_END,
+			ToString: func(code ecdef.ErrCode) string { return "" },
+		})
+	}, "MustRegisterGroup() must panic on overlapping ranges")
+
+	// Codes outside of any group
+	e = Err{Code: -1}
+	require.Equal(t, "", e.Group())
+	require.Equal(t, "unknown error code (-1)", e.Error())
+}
+
+func TestStackCapture(t *testing.T) {
+	// Stack is not recorded by default
+	e := New(ec.NotFound, "config")
+	require.Nil(t, e.StackTrace(), "stack must not be recorded by default")
+	require.Equal(t, "ec.NotFound config", e.Error())
+
+	// Per-call stack capture
+	e = Err{Code: ec.NotFound}.WithStack()
+	frames := e.StackTrace()
+	require.NotEmpty(t, frames)
+	require.True(t, strings.HasSuffix(frames[0].Function, "TestStackCapture"),
+		"first frame must be the caller, got '%s'", frames[0].Function)
+
+	// Global stack capture
+	SetStackCapture(true)
+	defer SetStackCapture(false)
+	inner := New(ec.NotFound, "inner")
+	outer := Wrap(inner, ec.Other, "outer")
+	require.NotEmpty(t, inner.StackTrace())
+	require.NotEmpty(t, outer.StackTrace())
+
+	// %v is not affected by recorded stack, %+v prints all frames of the chain
+	require.Equal(t, outer.Error(), fmt.Sprintf("%v", outer))
+	verbose := fmt.Sprintf("%+v", outer)
+	require.Contains(t, verbose, "caused by: ec.NotFound inner")
+	require.Equal(t, 2, strings.Count(verbose, "TestStackCapture"),
+		"both errors of the chain must print their frames:\n%s", verbose)
+}
+
+func TestFields(t *testing.T) {
+	e := Err{Code: ec.NotFound}.With(Path("/tmp/a b.txt"), Op("copy"))
+	require.Equal(t, `ec.NotFound [path="/tmp/a b.txt" op=copy]`, e.Error())
+	require.Equal(t, []Field{Path("/tmp/a b.txt"), Op("copy")}, e.Fields())
+
+	// With() does not modify the original error
+	e2 := e.With(Op("move"), UID(1000))
+	require.Equal(t, []Field{Path("/tmp/a b.txt"), Op("copy")}, e.Fields())
+	require.Equal(t, []Field{Path("/tmp/a b.txt"), Op("move"), UID(1000)}, e2.Fields())
+
+	// Fields are looked up through the whole chain, outer fields win
+	outer := Err{Code: ec.Other, Cause: e}.With(Op("backup"))
+	op, ok := outer.Field(KeyOp)
+	require.True(t, ok)
+	require.Equal(t, "backup", op)
+	path, ok := outer.Field(KeyPath)
+	require.True(t, ok)
+	require.Equal(t, "/tmp/a b.txt", path)
+	_, ok = outer.Field(KeyURL)
+	require.False(t, ok)
+	require.Equal(t, `ec.Other (other error) [op=backup]: `+
+		`ec.NotFound [path="/tmp/a b.txt" op=copy]`, outer.Error())
+
+	// Err with fields can be compared with errors.Is()
+	require.True(t, errors.Is(outer, Err{Code: ec.NotFound}))
+}
+
+func TestJSON(t *testing.T) {
+	_, err := os.Open("/not/existing/path")
+	e := Err{Code: ec.Other, Msg: "can't load",
+		Cause: Err{Code: ecfs.NotADir, Cause: err}.With(Path("/not"), UID(1000))}
+	data, err := json.Marshal(e)
+	require.Nil(t, err)
+
+	var decoded Err
+	err = json.Unmarshal(data, &decoded)
+	require.Nil(t, err)
+	require.True(t, decoded.Eq(ec.Other))
+	require.Equal(t, "can't load", decoded.Msg)
+	require.Equal(t, e.Error(), decoded.Error())
+	require.True(t, errors.Is(decoded, Err{Code: ecfs.NotADir}))
+	cause, ok := AsErr(decoded.Cause)
+	require.True(t, ok)
+	require.True(t, cause.IsFS())
+	require.Equal(t, []Field{Path("/not"), UID(1000)}, cause.Fields())
+	// Non-Err cause is kept as opaque text
+	opaque, ok := cause.Cause.(*OpaqueError)
+	require.True(t, ok, "expected *OpaqueError, got %T", cause.Cause)
+	require.Equal(t, "open /not/existing/path: no such file or directory", opaque.Error())
+
+	// Wire schema
+	var generic map[string]interface{}
+	require.Nil(t, json.Unmarshal(data, &generic))
+	require.Equal(t, float64(ec.Other), generic["code"])
+	require.Equal(t, "ec", generic["group"])
+	require.NotEmpty(t, generic["name"])
+	nested := generic["cause"].(map[string]interface{})
+	require.Equal(t, "ecfs", nested["group"])
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"key": "path", "value": "/not"},
+		map[string]interface{}{"key": "uid", "value": float64(1000)},
+	}, nested["context"])
+	text := nested["cause"].(map[string]interface{})
+	_, hasCode := text["code"]
+	require.False(t, hasCode, "opaque link must not have a code")
+
+	// NoError survives the round trip
+	data, err = json.Marshal(NoError)
+	require.Nil(t, err)
+	decoded = Err{Code: ec.Dummy}
+	require.Nil(t, json.Unmarshal(data, &decoded))
+	require.True(t, decoded.None())
+}
+
+// joined wraps several errors like errors.Join() of Go 1.20.
+type joined []error
+
+func (j joined) Error() string   { return fmt.Sprintf("%d joined errors", len(j)) }
+func (j joined) Unwrap() []error { return j }
+
+func TestJSONSeveralCauses(t *testing.T) {
+	e := Err{Code: ec.Other, Msg: "batch",
+		Cause: joined{Err{Code: ec.Key}.With(Path("/tmp/a")), errors.New("raw")}}
+	data, err := json.Marshal(e)
+	require.Nil(t, err)
+
+	var decoded Err
+	require.Nil(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, e.Error(), decoded.Error())
+	require.True(t, errors.Is(decoded, Err{Code: ec.Key}))
+	opaque, ok := decoded.Cause.(*OpaqueError)
+	require.True(t, ok, "expected *OpaqueError, got %T", decoded.Cause)
+	require.Len(t, opaque.Errors, 2)
+	member, ok := AsErr(opaque.Errors[0])
+	require.True(t, ok)
+	require.Equal(t, []Field{Path("/tmp/a")}, member.Fields())
+	require.Equal(t, "raw", opaque.Errors[1].Error())
+}
+
 func TestFromError(t *testing.T) {
 	// Create file without privileges must return ec.PermissionDenied
 	_, err := os.Create("/dummy.txt")
@@ -251,6 +461,18 @@ func BenchmarkErrorWithMessageAndCauseChainCreation(b *testing.B) {
 	}
 }
 
+// Creating errors with New() is as cheap as using a struct literal
+// while stack capture is disabled (default).
+// 20ns/op, 0 alloc
+func BenchmarkNew(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		e := New(ec.Dummy, "Short dummy description")
+		if e.Code != ec.Dummy {
+			log.Fatalf("Expected ec.Dummy, received %v", e)
+		}
+	}
+}
+
 // 14ns/op, 0 alloc
 func BenchmarkAsErr(b *testing.B) {
 	for i := 0; i < b.N; i++ {
@@ -274,7 +496,7 @@ func BenchmarkErrNone(b *testing.B) {
 	}
 }
 
-// 0.57ns/op, 0 alloc
+// 20ns/op, 0 alloc (group is looked up in ecdef registry)
 func BenchmarkErrIsBasic(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		if !dummyErr.IsBasic() {
@@ -282,3 +504,429 @@ func BenchmarkErrIsBasic(b *testing.B) {
 		}
 	}
 }
+
+func TestNetErrorCode(t *testing.T) {
+	var testData = []struct {
+		err  error
+		code ecdef.ErrCode
+	}{
+		{&net.DNSError{Err: "no such host", Name: "nonexistent.invalid", IsNotFound: true},
+			ecnet.DNSFailure},
+		{&net.DNSError{Err: "i/o timeout", Name: "example.com", IsTimeout: true}, ec.TimedOut},
+		{&url.Error{Op: "Get", URL: "https://example.com", Err: x509.UnknownAuthorityError{}},
+			ecnet.UntrustedCertificate},
+		{&url.Error{Op: "Get", URL: "https://example.com", Err: x509.HostnameError{Host: "example.com"}},
+			ecnet.HostnameMismatch},
+		{x509.CertificateInvalidError{Reason: x509.Expired}, ecnet.InvalidCertificate},
+		{tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"},
+			ecnet.TLSHandshake},
+		{&net.OpError{Op: "remote error", Err: errors.New("tls: bad certificate")},
+			ecnet.TLSHandshake},
+		{&net.AddrError{Err: "missing port in address", Addr: "example.com"}, ecnet.InvalidAddress},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("unknown")}, ecnet.Error},
+		{fmt.Errorf("wrapped: %w", net.ErrClosed), ec.AlreadyClosed},
+	}
+	for _, td := range testData {
+		code, ok := NetErrorCode(td.err)
+		require.True(t, ok)
+		require.Equal(t, td.code, code, "NetErrorCode(%v)", td.err)
+		e := FromError(td.err)
+		require.Equal(t, td.code, e.Code, "FromError(%v)", td.err)
+		require.Equal(t, td.err, e.Cause)
+	}
+	_, ok := NetErrorCode(errors.New("not a network error"))
+	require.False(t, ok)
+
+	// Real connection to a closed port
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	addr := l.Addr().String()
+	require.Nil(t, l.Close())
+	_, err = net.Dial("tcp", addr)
+	require.NotNil(t, err)
+	require.Equal(t, ecnet.ConnectionRefused, FromError(err).Code, "got %v", FromError(err))
+}
+
+func TestHTTPStatus(t *testing.T) {
+	e := Err{Code: echttp.NotFound_404}
+	require.True(t, e.IsHTTP() && e.IsHTTPClientError())
+	require.False(t, e.IsHTTPServerError() || e.IsHTTPRetryable())
+	require.Equal(t, "404 not found", e.Error())
+
+	for _, code := range []ecdef.ErrCode{echttp.TooManyRequests_429, echttp.BadGateway_502,
+		echttp.ServiceUnavailable_503, echttp.GatewayTimeout_504} {
+		e = Err{Code: code}
+		require.True(t, e.IsHTTPRetryable(), "%v must be retryable", e)
+	}
+	e = Err{Code: echttp.InternalServerError_500}
+	require.True(t, e.IsHTTPServerError())
+	require.False(t, e.IsHTTPClientError() || e.IsHTTPRetryable())
+	// Status codes without a name still belong to the http group
+	e = Err{Code: 599}
+	require.True(t, e.IsHTTPServerError())
+	require.Equal(t, "HTTP status code 599", e.Error())
+}
+
+func TestCodeNames(t *testing.T) {
+	e := Err{Code: ecnet.ConnectionRefused}
+	require.Equal(t, "ecnet.ConnectionRefused", e.Name())
+	require.Equal(t, "echttp.NotFound_404", ecdef.CodeName(echttp.NotFound_404))
+	require.Equal(t, "ec.NothingDone", ecdef.CodeName(ec.NothingDone))
+
+	var testData = []struct {
+		name string
+		code ecdef.ErrCode
+		ok   bool
+	}{
+		{"ec.NotFound", ec.NotFound, true},
+		{"ec.NoError", ec.NoError, true},
+		{"ecfs.CrossDevice", ecfs.CrossDevice, true},
+		{"echttp.TooManyRequests_429", echttp.TooManyRequests_429, true},
+		{"ecfs(1000250)", 1000250, true},
+		{"ecfs(1)", 0, false},
+		{"ec.NoSuchCode", 0, false},
+		{"NotFound", 0, false},
+		{"", 0, false},
+	}
+	for _, td := range testData {
+		code, ok := ecdef.ParseCodeName(td.name)
+		require.Equal(t, td.ok, ok, "ParseCodeName(%q)", td.name)
+		require.Equal(t, td.code, code, "ParseCodeName(%q)", td.name)
+	}
+
+	// Every listed code has a unique name that can be parsed back
+	all := ecdef.AllCodes()
+	names := map[string]bool{}
+	for _, info := range all {
+		require.False(t, names[info.Name], "duplicate name %s", info.Name)
+		names[info.Name] = true
+		require.True(t, ecdef.InGroup(info.Code, info.Group))
+		code, ok := ecdef.ParseCodeName(info.Name)
+		require.True(t, ok && code == info.Code, "ParseCodeName(%s)", info.Name)
+	}
+	require.True(t, names["ecnet.DNSFailure"] && names["ecsys.KeyboardInterrupt"])
+
+	netCodes, err := ecdef.MatchCodes("ecnet.*")
+	require.Nil(t, err)
+	require.NotEmpty(t, netCodes)
+	for _, info := range netCodes {
+		require.Equal(t, ecdef.NET_GROUP, info.Group)
+	}
+	sameCodes, err := ecdef.MatchCodes("ecnet")
+	require.Nil(t, err)
+	require.Equal(t, netCodes, sameCodes)
+	found, err := ecdef.MatchCodes("*.NotFound")
+	require.Nil(t, err)
+	require.Len(t, found, 1)
+	require.Equal(t, ec.NotFound, found[0].Code)
+	_, err = ecdef.MatchCodes("ec.[")
+	require.NotNil(t, err)
+
+	require.True(t, ecdef.CodeMatches(ecnet.ConnectionReset, "ecnet.*"))
+	require.True(t, ecdef.CodeMatches(ecnet.ConnectionReset, "ecnet.Conn*"))
+	require.False(t, ecdef.CodeMatches(ecfs.Busy, "ecnet.*"))
+	// Codes without a name match their group
+	require.True(t, ecdef.CodeMatches(1000250, "ecfs.*"))
+	require.False(t, ecdef.CodeMatches(1000250, "ecfs.Busy"))
+}
+
+func TestMultiErr(t *testing.T) {
+	var me MultiErr
+	require.True(t, me.None())
+	require.Equal(t, NoError, me.ToErr())
+	me.Add(nil)
+	me.Add(NoError)
+	require.True(t, me.None(), "nil and NoError must be ignored")
+
+	me.Msg = "copy"
+	me.AddAt(0, Err{Code: ec.NotFound})
+	me.AddKey("/tmp/a", &fs.PathError{Op: "open", Path: "/tmp/a", Err: fs.ErrPermission})
+	require.True(t, me.Some())
+	require.Equal(t, 2, me.Len())
+	require.True(t, me.OverallCode() == ec.Multiple)
+
+	e := me.ToErr()
+	require.True(t, e.Some() && e.Code == ec.Multiple)
+	require.Equal(t, "ec.Multiple (multiple errors) copy: 2 errors: [0] ec.NotFound; "+
+		"[/tmp/a] open /tmp/a: permission denied", e.Error())
+	// errors.Is() and errors.As() work across all members
+	require.True(t, errors.Is(e, Err{Code: ec.NotFound}))
+	require.True(t, e.Is(fs.ErrPermission))
+	require.False(t, errors.Is(e, fs.ErrNotExist))
+	var pathErr *fs.PathError
+	require.True(t, errors.As(e, &pathErr))
+	require.Equal(t, "/tmp/a", pathErr.Path)
+	var multi *MultiErr
+	require.True(t, errors.As(e, &multi))
+	require.Equal(t, -1, multi.Items[1].Index)
+	// The result of ToErr() is not affected by further additions
+	me.Add(Err{Code: ec.Dummy})
+	require.Equal(t, 2, multi.Len())
+
+	// Overall code is the shared code of all members
+	me = MultiErr{}
+	me.Add(Err{Code: ec.NotFound})
+	me.Add(Err{Code: ec.NotFound}.With(Path("/a")))
+	require.True(t, me.ToErr().Code == ec.NotFound)
+	me.Code = ec.Dummy
+	require.True(t, me.ToErr().Code == ec.Dummy)
+
+	// Members survive the JSON round trip
+	data, err := json.Marshal(e)
+	require.Nil(t, err)
+	var decoded Err
+	require.Nil(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, e.Error(), decoded.Error())
+	require.True(t, errors.Is(decoded, Err{Code: ec.NotFound}))
+}
+
+func TestErr_As(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "/a", Err: fs.ErrNotExist}
+	exitErr := &exec.ExitError{}
+	inner := Err{Code: ec.NotFound, Cause: pathErr}.With(Path("/a"))
+	e := Err{Code: ec.Dummy, Cause: fmt.Errorf("wrapped: %w", inner)}
+
+	// Error codes can be used as errors.Is() targets
+	require.True(t, errors.Is(e, Code(ec.Dummy)))
+	require.True(t, errors.Is(e, Code(ec.NotFound)))
+	require.True(t, e.Is(Code(ec.NotFound)))
+	require.False(t, errors.Is(e, Code(ec.PermissionDenied)))
+	require.True(t, errors.Is(e, fs.ErrNotExist))
+	// Is() methods of the wrapped errors are respected
+	require.True(t, errors.Is(Err{Code: ec.Dummy, Cause: e}, Err{Code: ec.NotFound}))
+
+	// Nested Err can be extracted by value and by pointer
+	var asErr Err
+	require.True(t, errors.As(e, &asErr))
+	require.True(t, asErr.Code == ec.Dummy)
+	var asPtr *Err
+	require.True(t, errors.As(fmt.Errorf("wrapped: %w", inner), &asPtr))
+	require.True(t, asPtr.Code == ec.NotFound)
+	require.Equal(t, "/a", asPtr.Fields()[0].Value)
+
+	// Typed cause extraction
+	p, ok := CauseAs[*fs.PathError](e)
+	require.True(t, ok)
+	require.Equal(t, pathErr, p)
+	_, ok = CauseAs[*exec.ExitError](e)
+	require.False(t, ok)
+	x, ok := CauseAs[*exec.ExitError](Err{Code: ec.ProcessExit, Cause: exitErr})
+	require.True(t, ok)
+	require.Equal(t, exitErr, x)
+	_, ok = CauseAs[*fs.PathError](nil)
+	require.False(t, ok)
+}
+
+func TestStatusMapping(t *testing.T) {
+	var testData = []struct {
+		code     ecdef.ErrCode
+		status   int
+		exitCode int
+	}{
+		{ec.NoError, 200, 0},
+		{ec.NotFound, 404, 2},
+		{ec.PermissionDenied, 403, EX_NOPERM},
+		{ec.InvalidInput, 400, EX_USAGE},
+		{ec.TimedOut, 504, EX_TEMPFAIL},
+		{ecfs.NotADir, DEFAULT_HTTP_STATUS, EX_IOERR},
+		{ecnet.ConnectionRefused, 502, EX_UNAVAILABLE},
+		{echttp.NotFound_404, 404, EX_PROTOCOL},
+		{echttp.ServiceUnavailable_503, 503, EX_PROTOCOL},
+		{ec.Dummy, DEFAULT_HTTP_STATUS, DEFAULT_EXIT_CODE},
+	}
+	for _, td := range testData {
+		e := Err{Code: td.code}
+		require.Equal(t, td.status, e.HTTPStatus(), "%s", e.Name())
+		require.Equal(t, td.exitCode, e.ExitCode(), "%s", e.Name())
+	}
+
+	// Exit code of a sub-process is passed through
+	err := exec.Command("sh", "-c", "exit 3").Run()
+	e := FromError(err)
+	require.True(t, e.Code == ec.ProcessExit)
+	require.Equal(t, 3, e.ExitCode())
+
+	// Mappings can be changed
+	RegisterHTTPStatus(testGroupError, 418)
+	RegisterExitCode(testGroupError, 42)
+	e = Err{Code: testGroupError}
+	require.Equal(t, 418, e.HTTPStatus())
+	require.Equal(t, 42, e.ExitCode())
+	RegisterGroupHTTPStatus(ecdef.MATH_GROUP, 422)
+	RegisterGroupExitCode(ecdef.MATH_GROUP, EX_DATAERR)
+	e = Err{Code: ecmath.ZeroDivision}
+	require.Equal(t, 422, e.HTTPStatus())
+	require.Equal(t, EX_DATAERR, e.ExitCode())
+}
+
+func TestLocalizedError(t *testing.T) {
+	fsys := fstest.MapFS{
+		"i18n/de.json": {Data: []byte(`{"ec.NotFound": "nicht gefunden",
+			"ecfs.NotADir": "kein Verzeichnis", "2000000000": "Testfehler"}`)},
+		"i18n/de-AT.json": {Data: []byte(`{"ec.NotFound": "net gfundn"}`)},
+		"bad/fr.json":     {Data: []byte(`{"ec.NoSuchCode": "?"}`)},
+		"broken/fr.json":  {Data: []byte(`{`)},
+	}
+	e := LoadMessages(fsys, "i18n")
+	require.True(t, e.None(), "%v", e)
+
+	require.Equal(t, "nicht gefunden", LocalizedCodeString(ec.NotFound, "de"))
+	require.Equal(t, "net gfundn", LocalizedCodeString(ec.NotFound, "de_AT"))
+	// Fallback to the base language, then to English
+	require.Equal(t, "kein Verzeichnis", LocalizedCodeString(ecfs.NotADir, "de-AT"))
+	require.Equal(t, "Testfehler", LocalizedCodeString(testGroupError, "de-CH"))
+	require.Equal(t, "ec.NotFound", LocalizedCodeString(ec.NotFound, "fr"))
+	require.Equal(t, "ec.TimedOut", LocalizedCodeString(ec.TimedOut, "de"))
+
+	// The whole chain is localized
+	e = Err{Code: ec.NotFound, Msg: "config",
+		Cause: Err{Code: ecfs.NotADir}.With(Path("/etc"))}
+	require.Equal(t, "nicht gefunden config: kein Verzeichnis [path=/etc]",
+		e.LocalizedError("de"))
+	require.Equal(t, e.Error(), e.LocalizedError("en"))
+
+	var me MultiErr
+	me.AddKey("/a", Err{Code: ec.NotFound})
+	me.AddKey("/b", errors.New("other"))
+	e = me.ToErr()
+	require.Equal(t, "ec.Multiple (multiple errors): 2 errors: [/a] nicht gefunden; [/b] other",
+		e.LocalizedError("de"))
+
+	// Messages can be registered programmatically
+	RegisterMessages("uk", map[ecdef.ErrCode]string{ec.NotFound: "не знайдено"})
+	require.Equal(t, "не знайдено", LocalizedCodeString(ec.NotFound, "UK"))
+
+	e = LoadMessages(fsys, "bad")
+	require.True(t, e.Code == ec.InvalidData, "%v", e)
+	path, _ := e.Field(KeyPath)
+	require.Equal(t, "bad/fr.json", path)
+	e = LoadMessages(fsys, "broken")
+	require.True(t, e.Code == ec.InvalidData, "%v", e)
+	e = LoadMessages(fsys, "missing")
+	require.True(t, e.Code == ec.NotFound, "%v", e)
+	require.Equal(t, "ec.NotFound", LocalizedCodeString(ec.NotFound, "fr"))
+}
+
+func TestRecover(t *testing.T) {
+	divide := func(a, b int) (r int, e Err) {
+		defer Recover(&e)
+		return a / b, NoError
+	}
+	r, e := divide(6, 3)
+	require.True(t, e.None())
+	require.Equal(t, 2, r)
+
+	_, e = divide(1, 0)
+	require.True(t, e.Code == ec.Panic, "%v", e)
+	p, ok := CauseAs[*PanicError](e)
+	require.True(t, ok)
+	require.Contains(t, p.Error(), "divide by zero")
+	require.Contains(t, string(p.Stack), "TestRecover")
+	var runtimeErr interface{ RuntimeError() }
+	require.True(t, errors.As(e, &runtimeErr))
+
+	// Panics with Err values keep them in the chain
+	panicErr := func() (e Err) {
+		defer Recover(&e)
+		panic(Err{Code: ec.InvalidData})
+	}()
+	require.True(t, panicErr.Code == ec.Panic)
+	require.True(t, errors.Is(panicErr, Code(ec.InvalidData)))
+	require.Equal(t, EX_SOFTWARE, panicErr.ExitCode())
+}
+
+func TestGo(t *testing.T) {
+	e := <-Go(func() Err { return Err{Code: ec.NotFound} })
+	require.True(t, e.Code == ec.NotFound)
+
+	e = <-Go(func() Err { panic("boom") })
+	require.True(t, e.Code == ec.Panic)
+	require.Equal(t, "ec.Panic (recovered panic): boom", e.Error())
+
+	done := Go(func() Err { return NoError })
+	e = <-done
+	require.True(t, e.None())
+	_, open := <-done
+	require.False(t, open)
+}
+
+func TestCodeSet(t *testing.T) {
+	var empty CodeSet
+	require.False(t, empty.Contains(ec.NoError))
+
+	s := NewCodeSet(ec.NotFound, ec.TimedOut)
+	require.True(t, s.Contains(ec.NotFound))
+	require.False(t, s.Contains(ec.Other))
+
+	// Sets are immutable
+	extended := s.With(ec.Other).WithRange(ecdef.MATH_RANGE_BEGIN, ecdef.MATH_RANGE_END)
+	require.False(t, s.Contains(ec.Other))
+	require.True(t, extended.Contains(ec.Other))
+	require.True(t, extended.Contains(ecmath.ZeroDivision))
+	require.Equal(t, []ecdef.ErrCode{ec.NotFound, ec.TimedOut}, s.Codes())
+	require.Equal(t, []CodeRange{{ecdef.MATH_RANGE_BEGIN, ecdef.MATH_RANGE_END}}, extended.Ranges())
+
+	union := NewCodeSet(ec.Dummy).Union(NotFoundLike, CodeSet{}.WithGroup(ecdef.NET_GROUP))
+	require.True(t, union.Contains(ec.Dummy))
+	require.True(t, union.Contains(echttp.NotFound_404))
+	require.True(t, union.Contains(ecnet.DNSFailure))
+	require.False(t, NotFoundLike.Contains(ec.Dummy))
+	require.False(t, CodeSet{}.WithGroup("no-such-group").Contains(ec.NoError))
+	require.False(t, CodeSet{}.WithRange(10, 5).Contains(7))
+
+	// Predefined sets
+	require.True(t, Transient.Contains(echttp.ServiceUnavailable_503))
+	require.True(t, PermissionLike.Contains(ecauth.Credentials))
+	require.True(t, CallerBug.Contains(ec.InvalidInput))
+	require.False(t, Transient.Contains(ec.InvalidInput))
+
+	// The whole chain is checked, including errors collected by MultiErr
+	var me MultiErr
+	me.Add(Err{Code: ec.InvalidData})
+	me.Add(fmt.Errorf("wrapped: %w", Err{Code: ecnet.ConnectionReset, Msg: "peer"}))
+	e := Err{Code: ec.Other, Cause: me.ToErr()}
+	require.True(t, e.InSet(Transient))
+	require.False(t, e.InSet(PermissionLike))
+	found, ok := e.FindInSet(Transient)
+	require.True(t, ok)
+	require.Equal(t, "peer", found.Msg)
+	_, ok = e.FindInSet(NotFoundLike)
+	require.False(t, ok)
+}
+
+func TestFromContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	require.Equal(t, NoError, FromContext(ctx))
+	cancel()
+	e := FromContext(ctx)
+	require.True(t, e.Code == ec.Canceled, "%v", e)
+	require.True(t, errors.Is(e, context.Canceled))
+
+	ctx, cancel = context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	e = FromContext(ctx)
+	require.True(t, e.Code == ec.DeadlineExceeded, "%v", e)
+	require.True(t, errors.Is(e, context.DeadlineExceeded))
+
+	// Context errors are recognized anywhere in the chain,
+	// e.g. wrapped by *url.Error returned from http.Client
+	var testData = []struct {
+		err  error
+		code ecdef.ErrCode
+	}{
+		{context.Canceled, ec.Canceled},
+		{context.DeadlineExceeded, ec.DeadlineExceeded},
+		{&url.Error{Op: "Get", URL: "https://example.com", Err: context.Canceled}, ec.Canceled},
+		{&url.Error{Op: "Get", URL: "https://example.com",
+			Err: fmt.Errorf("dial: %w", context.DeadlineExceeded)}, ec.DeadlineExceeded},
+	}
+	for _, td := range testData {
+		code, ok := ContextErrorCode(td.err)
+		require.True(t, ok)
+		require.Equal(t, td.code, code, "ContextErrorCode(%v)", td.err)
+		require.Equal(t, td.code, FromError(td.err).Code, "FromError(%v)", td.err)
+	}
+	_, ok := ContextErrorCode(os.ErrDeadlineExceeded)
+	require.False(t, ok)
+	require.Equal(t, ec.TimedOut, FromError(os.ErrDeadlineExceeded).Code)
+}
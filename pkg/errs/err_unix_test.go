@@ -0,0 +1,79 @@
+//go:build !windows
+// +build !windows
+
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecdef"
+	"github.com/iotanbo/igu/pkg/ecfs"
+	"github.com/iotanbo/igu/pkg/ecnet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromErrorErrno(t *testing.T) {
+	var testData = []struct {
+		err  error
+		code ecdef.ErrCode
+	}{
+		{&os.PathError{Op: "open", Path: "/a", Err: syscall.ENOENT}, ec.NotFound},
+		{&os.PathError{Op: "open", Path: "/a", Err: syscall.EACCES}, ec.PermissionDenied},
+		{&os.PathError{Op: "write", Path: "/a", Err: syscall.ENOSPC}, ecfs.NoSpace},
+		{&os.PathError{Op: "open", Path: "/a/b", Err: syscall.ENOTDIR}, ecfs.NotADir},
+		{&os.PathError{Op: "open", Path: "/a", Err: syscall.EISDIR}, ecfs.NotAFile},
+		{&os.LinkError{Op: "rename", Old: "/a", New: "/b", Err: syscall.EXDEV}, ecfs.CrossDevice},
+		{&os.LinkError{Op: "rename", Old: "/a", New: "/b", Err: syscall.ENOTEMPTY}, ecfs.DirNotEmpty},
+		{os.NewSyscallError("connect", syscall.ECONNREFUSED), ecnet.ConnectionRefused},
+		{os.NewSyscallError("bind", syscall.EADDRINUSE), ecnet.AddrInUse},
+		{os.NewSyscallError("read", syscall.ETIMEDOUT), ec.TimedOut},
+		// Errno wrapped by other errors
+		{fmt.Errorf("wrapped: %w", &os.PathError{Op: "open", Path: "/a", Err: syscall.EXDEV}),
+			ecfs.CrossDevice},
+		// Sentinel errors
+		{os.ErrNotExist, ec.NotFound},
+		{fmt.Errorf("wrapped: %w", os.ErrExist), ec.AlreadyExists},
+		{errors.New("unknown"), ec.Other},
+	}
+	for _, td := range testData {
+		e := FromError(td.err)
+		require.True(t, e.Eq(td.code), "FromError(%v): expected %d, got %v",
+			td.err, td.code, e)
+		require.Equal(t, td.err, e.Cause, "FromError() must keep the original error")
+	}
+
+	// Real file system errors
+	tmpDir := t.TempDir()
+	_, err := os.Open(tmpDir + "/not_exists")
+	require.True(t, FromError(err).Code == ec.NotFound)
+	err = os.Mkdir(tmpDir, 0755)
+	require.True(t, FromError(err).Code == ec.AlreadyExists)
+	f := tmpDir + "/file.txt"
+	require.Nil(t, os.WriteFile(f, []byte("file"), 0644))
+	_, err = os.Open(f + "/child")
+	require.True(t, FromError(err).Code == ecfs.NotADir, "got %v", FromError(err))
+}
+
+func TestRegisterErrorMapper(t *testing.T) {
+	errCustom := errors.New("custom error")
+	RegisterErrorMapper(func(err error) (ecdef.ErrCode, bool) {
+		if errors.Is(err, errCustom) {
+			return ec.Dummy, true
+		}
+		return ec.Other, false
+	})
+	require.True(t, FromError(fmt.Errorf("wrapped: %w", errCustom)).Code == ec.Dummy)
+	// Other errors are not affected
+	require.True(t, FromError(os.ErrNotExist).Code == ec.NotFound)
+
+	// Errno mappings can be overridden
+	const errno = syscall.Errno(0xFFFF)
+	require.True(t, FromError(errno).Code == ec.Other)
+	RegisterErrno(errno, ec.Dummy)
+	require.True(t, FromError(errno).Code == ec.Dummy)
+}
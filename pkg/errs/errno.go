@@ -0,0 +1,73 @@
+package errs
+
+import (
+	"sync"
+	"syscall"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecdef"
+)
+
+// ErrorMapper inspects err and returns the matching error code and true,
+// or any code and false if it does not recognize err.
+// Mappers are registered with RegisterErrorMapper() and allow applications
+// to teach FromError() about their own error types.
+type ErrorMapper func(err error) (ecdef.ErrCode, bool)
+
+var mappersMu sync.RWMutex
+var errorMappers []ErrorMapper
+var errnoOverrides = map[syscall.Errno]ecdef.ErrCode{}
+
+// RegisterErrorMapper adds m to the mappers consulted by FromError()
+// before the built-in rules. Mappers registered later are consulted first.
+// Usage example:
+//
+//	func init() {
+//		RegisterErrorMapper(func(err error) (ecdef.ErrCode, bool) {
+//			if errors.Is(err, sql.ErrNoRows) {
+//				return ec.NotFound, true
+//			}
+//			return ec.Other, false
+//		})
+//	}
+func RegisterErrorMapper(m ErrorMapper) {
+	mappersMu.Lock()
+	defer mappersMu.Unlock()
+	errorMappers = append(errorMappers, m)
+}
+
+// RegisterErrno makes FromError() map errno to code,
+// overriding the default mapping if any.
+func RegisterErrno(errno syscall.Errno, code ecdef.ErrCode) {
+	mappersMu.Lock()
+	defer mappersMu.Unlock()
+	errnoOverrides[errno] = code
+}
+
+// codeFromMappers returns the code produced by the first registered
+// mapper that recognizes err.
+func codeFromMappers(err error) (ecdef.ErrCode, bool) {
+	mappersMu.RLock()
+	defer mappersMu.RUnlock()
+	for i := len(errorMappers) - 1; i >= 0; i-- {
+		if code, ok := errorMappers[i](err); ok {
+			return code, true
+		}
+	}
+	return 0, false
+}
+
+// ErrnoToCode returns the error code errno is mapped to and true,
+// or ec.Other and false if there is no mapping.
+func ErrnoToCode(errno syscall.Errno) (ecdef.ErrCode, bool) {
+	mappersMu.RLock()
+	code, ok := errnoOverrides[errno]
+	mappersMu.RUnlock()
+	if ok {
+		return code, true
+	}
+	if code, ok := defaultErrnoTable[errno]; ok {
+		return code, true
+	}
+	return ec.Other, false
+}
@@ -0,0 +1,74 @@
+//go:build !windows
+// +build !windows
+
+package errs
+
+import (
+	"syscall"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecdef"
+	"github.com/iotanbo/igu/pkg/ecfs"
+	"github.com/iotanbo/igu/pkg/ecnet"
+)
+
+// Default mapping of unix errno values to error codes.
+var defaultErrnoTable = map[syscall.Errno]ecdef.ErrCode{
+	// Basic errors
+	syscall.EPERM:           ec.PermissionDenied,
+	syscall.EACCES:          ec.PermissionDenied,
+	syscall.EROFS:           ec.PermissionDenied,
+	syscall.ENOENT:          ec.NotFound,
+	syscall.ESRCH:           ec.NotFound,
+	syscall.ENXIO:           ec.NotFound,
+	syscall.ENODEV:          ec.NotFound,
+	syscall.EEXIST:          ec.AlreadyExists,
+	syscall.EBADF:           ec.AlreadyClosed,
+	syscall.EINVAL:          ec.InvalidInput,
+	syscall.E2BIG:           ec.InvalidInput,
+	syscall.EINTR:           ec.Interrupted,
+	syscall.EAGAIN:          ec.WouldBlock,
+	syscall.EPIPE:           ec.BrokenPipe,
+	syscall.ENOMEM:          ec.Memory,
+	syscall.ENOSYS:          ec.NotImplemented,
+	syscall.ENOTSUP:         ec.Unsupported,
+	syscall.EPROTONOSUPPORT: ec.Unsupported,
+	syscall.EAFNOSUPPORT:    ec.Unsupported,
+	syscall.ETIMEDOUT:       ec.TimedOut,
+	syscall.EILSEQ:          ec.InvalidData,
+	// File system errors
+	syscall.EIO:          ecfs.Error,
+	syscall.ENOTDIR:      ecfs.NotADir,
+	syscall.EISDIR:       ecfs.NotAFile,
+	syscall.EFBIG:        ecfs.FileTooLarge,
+	syscall.ENOSPC:       ecfs.NoSpace,
+	syscall.EDQUOT:       ecfs.QuotaExceeded,
+	syscall.EXDEV:        ecfs.CrossDevice,
+	syscall.ENAMETOOLONG: ecfs.NameTooLong,
+	syscall.ENOTEMPTY:    ecfs.DirNotEmpty,
+	syscall.ELOOP:        ecfs.SymlinkLoop,
+	syscall.EMFILE:       ecfs.TooManyOpenFiles,
+	syscall.ENFILE:       ecfs.TooManyOpenFiles,
+	syscall.EMLINK:       ecfs.TooManyLinks,
+	syscall.EBUSY:        ecfs.Busy,
+	syscall.ETXTBSY:      ecfs.Busy,
+	// Network errors
+	syscall.ECONNREFUSED:  ecnet.ConnectionRefused,
+	syscall.ECONNRESET:    ecnet.ConnectionReset,
+	syscall.ENETRESET:     ecnet.ConnectionReset,
+	syscall.ECONNABORTED:  ecnet.ConnectionAborted,
+	syscall.ENOTCONN:      ecnet.NotConnected,
+	syscall.EADDRINUSE:    ecnet.AddrInUse,
+	syscall.EADDRNOTAVAIL: ecnet.AddrNotAvailable,
+	syscall.EHOSTUNREACH:  ecnet.HostUnreachable,
+	syscall.ENETUNREACH:   ecnet.NetworkUnreachable,
+	syscall.ENETDOWN:      ecnet.NetworkDown,
+}
+
+func init() {
+	// EOPNOTSUPP is same as ENOTSUP on linux but differs on other systems,
+	// so it can't be a key in the map literal above.
+	if _, ok := defaultErrnoTable[syscall.EOPNOTSUPP]; !ok {
+		defaultErrnoTable[syscall.EOPNOTSUPP] = ec.Unsupported
+	}
+}
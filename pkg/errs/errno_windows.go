@@ -0,0 +1,109 @@
+package errs
+
+import (
+	"syscall"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecdef"
+	"github.com/iotanbo/igu/pkg/ecfs"
+	"github.com/iotanbo/igu/pkg/ecnet"
+)
+
+// Windows system error codes that are not defined in the syscall package,
+// see https://docs.microsoft.com/en-us/windows/win32/debug/system-error-codes
+const (
+	errorInvalidFunction     syscall.Errno = 1
+	errorTooManyOpenFiles    syscall.Errno = 4
+	errorInvalidHandle       syscall.Errno = 6
+	errorNotEnoughMemory     syscall.Errno = 8
+	errorWriteProtect        syscall.Errno = 19
+	errorNotSameDevice       syscall.Errno = 17
+	errorSharingViolation    syscall.Errno = 32
+	errorLockViolation       syscall.Errno = 33
+	errorHandleDiskFull      syscall.Errno = 39
+	errorNotSupported        syscall.Errno = 50
+	errorInvalidParameter    syscall.Errno = 87
+	errorDiskFull            syscall.Errno = 112
+	errorInvalidName         syscall.Errno = 123
+	errorBusy                syscall.Errno = 170
+	errorFilenameExcedRange  syscall.Errno = 206
+	errorDirectory           syscall.Errno = 267
+	errorCantResolveFilename syscall.Errno = 1921
+	errorDiskQuotaExceeded   syscall.Errno = 1295
+	errorTimeout             syscall.Errno = 1460
+	wsaeWouldBlock           syscall.Errno = 10035
+	wsaeAddrInUse            syscall.Errno = 10048
+	wsaeAddrNotAvail         syscall.Errno = 10049
+	wsaeNetDown              syscall.Errno = 10050
+	wsaeNetUnreach           syscall.Errno = 10051
+	wsaeNotConn              syscall.Errno = 10057
+	wsaeTimedOut             syscall.Errno = 10060
+	wsaeConnRefused          syscall.Errno = 10061
+	wsaeHostUnreach          syscall.Errno = 10065
+	wsaeAfNoSupport          syscall.Errno = 10047
+	wsaeProtoNoSupport       syscall.Errno = 10043
+	errorConnectionRefused   syscall.Errno = 1225
+	errorHostUnreachable     syscall.Errno = 1232
+	errorNetworkUnreachable  syscall.Errno = 1231
+	errorConnectionAborted   syscall.Errno = 1236
+)
+
+// Default mapping of windows system error codes to error codes.
+var defaultErrnoTable = map[syscall.Errno]ecdef.ErrCode{
+	// Basic errors
+	syscall.ERROR_FILE_NOT_FOUND:     ec.NotFound,
+	syscall.ERROR_PATH_NOT_FOUND:     ec.NotFound,
+	syscall.ERROR_MOD_NOT_FOUND:      ec.NotFound,
+	syscall.ERROR_PROC_NOT_FOUND:     ec.NotFound,
+	syscall.ERROR_ENVVAR_NOT_FOUND:   ec.NotFound,
+	syscall.ERROR_NOT_FOUND:          ec.NotFound,
+	syscall.ERROR_ACCESS_DENIED:      ec.PermissionDenied,
+	syscall.ERROR_PRIVILEGE_NOT_HELD: ec.PermissionDenied,
+	errorWriteProtect:                ec.PermissionDenied,
+	syscall.WSAEACCES:                ec.PermissionDenied,
+	syscall.ERROR_FILE_EXISTS:        ec.AlreadyExists,
+	syscall.ERROR_ALREADY_EXISTS:     ec.AlreadyExists,
+	syscall.ERROR_BROKEN_PIPE:        ec.BrokenPipe,
+	syscall.ERROR_HANDLE_EOF:         ec.UnexpectedEof,
+	syscall.ERROR_OPERATION_ABORTED:  ec.Interrupted,
+	errorInvalidHandle:               ec.AlreadyClosed,
+	errorInvalidFunction:             ec.InvalidInput,
+	errorInvalidParameter:            ec.InvalidInput,
+	errorNotEnoughMemory:             ec.Memory,
+	errorNotSupported:                ec.Unsupported,
+	wsaeAfNoSupport:                  ec.Unsupported,
+	wsaeProtoNoSupport:               ec.Unsupported,
+	errorTimeout:                     ec.TimedOut,
+	wsaeTimedOut:                     ec.TimedOut,
+	wsaeWouldBlock:                   ec.WouldBlock,
+	// Windows-specific error when it encounters a unix symlink
+	errorCantResolveFilename: ec.SymlinksNotSupported,
+	// File system errors
+	errorDirectory:              ecfs.NotADir,
+	errorInvalidName:            ecfs.InvalidPath,
+	errorDiskFull:               ecfs.NoSpace,
+	errorHandleDiskFull:         ecfs.NoSpace,
+	errorDiskQuotaExceeded:      ecfs.QuotaExceeded,
+	errorNotSameDevice:          ecfs.CrossDevice,
+	errorFilenameExcedRange:     ecfs.NameTooLong,
+	syscall.ERROR_DIR_NOT_EMPTY: ecfs.DirNotEmpty,
+	errorTooManyOpenFiles:       ecfs.TooManyOpenFiles,
+	errorSharingViolation:       ecfs.Busy,
+	errorLockViolation:          ecfs.Busy,
+	errorBusy:                   ecfs.Busy,
+	// Network errors
+	wsaeConnRefused:               ecnet.ConnectionRefused,
+	errorConnectionRefused:        ecnet.ConnectionRefused,
+	syscall.WSAECONNRESET:         ecnet.ConnectionReset,
+	syscall.ERROR_NETNAME_DELETED: ecnet.ConnectionReset,
+	syscall.WSAECONNABORTED:       ecnet.ConnectionAborted,
+	errorConnectionAborted:        ecnet.ConnectionAborted,
+	wsaeNotConn:                   ecnet.NotConnected,
+	wsaeAddrInUse:                 ecnet.AddrInUse,
+	wsaeAddrNotAvail:              ecnet.AddrNotAvailable,
+	wsaeHostUnreach:               ecnet.HostUnreachable,
+	errorHostUnreachable:          ecnet.HostUnreachable,
+	wsaeNetUnreach:                ecnet.NetworkUnreachable,
+	errorNetworkUnreachable:       ecnet.NetworkUnreachable,
+	wsaeNetDown:                   ecnet.NetworkDown,
+}
@@ -3,19 +3,23 @@ package errs
 import (
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"os/exec"
-	"reflect"
+	"syscall"
 
 	"github.com/iotanbo/igu/pkg/ec"
-	"github.com/iotanbo/igu/pkg/ecauth"
-	"github.com/iotanbo/igu/pkg/ecdb"
 	"github.com/iotanbo/igu/pkg/ecdef"
-	"github.com/iotanbo/igu/pkg/ecfs"
 	"github.com/iotanbo/igu/pkg/echttp"
-	"github.com/iotanbo/igu/pkg/ecmath"
-	"github.com/iotanbo/igu/pkg/ecnet"
-	"github.com/iotanbo/igu/pkg/ecsys"
+
+	// Error code packages register their groups in ecdef at init time.
+	_ "github.com/iotanbo/igu/pkg/ecauth"
+	_ "github.com/iotanbo/igu/pkg/ecdb"
+	_ "github.com/iotanbo/igu/pkg/ecfs"
+	_ "github.com/iotanbo/igu/pkg/ecmath"
+	_ "github.com/iotanbo/igu/pkg/ecnet"
+	_ "github.com/iotanbo/igu/pkg/ecsys"
 )
 
 // NoError is same as Err{}. It's a convenience global variable that makes it easy to return
@@ -30,10 +34,37 @@ var NoError = Err{}
 // assign a custom function that converts your custom error codes to strings
 // to this variable at program start (e.g. in the main init() function).
 // Example is provided in documentation.
+// Libraries that need their own error codes should rather
+// register a separate group with ecdef.RegisterGroup().
 var AppECToString = func(code ecdef.ErrCode) string {
 	return fmt.Sprintf("undefined app-specific error (%d)", code)
 }
 
+// AppECName is a function object that returns the symbolic name
+// of a custom app-specific error code, e.g. "app.ConfigMissing",
+// or an empty string if the code has no name.
+// Like AppECToString, it may be assigned at program start,
+// e.g. to a converter generated by cmd/ecgen.
+var AppECName = func(code ecdef.ErrCode) string { return "" }
+
+// AppECCodes is a function object that returns all custom app-specific
+// error codes, it allows ecdef.ParseCodeName() and ecdef.AllCodes()
+// to work with them. Like AppECToString, it may be assigned at program start.
+var AppECCodes = func() []ecdef.ErrCode { return nil }
+
+func init() {
+	// The app-specific group is registered here rather than in ecdef
+	// because its converter may be re-assigned by user at any time.
+	ecdef.MustRegisterGroup(ecdef.Group{
+		Name:     ecdef.APP_GROUP,
+		Begin:    ecdef.APP_RANGE_BEGIN,
+		End:      ecdef.APP_RANGE_END,
+		ToString: func(code ecdef.ErrCode) string { return AppECToString(code) },
+		CodeName: func(code ecdef.ErrCode) string { return AppECName(code) },
+		Codes:    func() []ecdef.ErrCode { return AppECCodes() },
+	})
+}
+
 // Err is a simple yet effective error type that implements error interface
 // and is used by Iotanbo Go Utils (IGU) library.
 // For better performance it is recommended to return it by value.
@@ -41,6 +72,8 @@ var AppECToString = func(code ecdef.ErrCode) string {
 //	e0 := Err{} // no error
 //	e1 := Err{Code: ec.Dummy}
 //	e2 := Err{Code: ec.Other, Msg: "other error from dummy", Cause: e1}
+//	e3 := New(ec.NotFound, "config file") // records call stack if enabled
+//	e4 := Err{Code: ec.NotFound}.With(Path("/etc/app.conf")) // adds context
 type Err struct {
 	// Error code
 	Code ecdef.ErrCode
@@ -48,90 +81,94 @@ type Err struct {
 	Msg string
 	// The error that caused this error (optional, typically nil).
 	Cause error
+	// Call stack recorded by New(), Wrap() or WithStack() (optional, typically nil).
+	stack *stack
+	// Context fields attached by With() (optional, typically nil).
+	fields *[]Field
 }
 
 // IsBasic() checks that e.Code belongs to the basic group of error codes
 // defined in the ec package.
-func (e *Err) IsBasic() bool {
-	return e.Code >= ecdef.BASIC_RANGE_BEGIN && e.Code <= ecdef.BASIC_RANGE_END
-}
+func (e *Err) IsBasic() bool { return e.InGroup(ecdef.BASIC_GROUP) }
 
 // IsAuth() checks that e.Code belongs to the auth group of error codes
 // defined in the ecauth package.
-func (e *Err) IsAuth() bool {
-	return e.Code >= ecdef.AUTH_RANGE_BEGIN && e.Code <= ecdef.AUTH_RANGE_END
-}
+func (e *Err) IsAuth() bool { return e.InGroup(ecdef.AUTH_GROUP) }
 
 // IsDB() checks that e.Code belongs to the database group of error codes
 // defined in the ecdb package.
-func (e *Err) IsDB() bool {
-	return e.Code >= ecdef.DB_RANGE_BEGIN && e.Code <= ecdef.DB_RANGE_END
-}
+func (e *Err) IsDB() bool { return e.InGroup(ecdef.DB_GROUP) }
 
 // IsMath() checks that e.Code belongs to the math group of error codes
 // defined in the ecmath package.
-func (e *Err) IsMath() bool {
-	return e.Code >= ecdef.MATH_RANGE_BEGIN && e.Code <= ecdef.MATH_RANGE_END
-}
+func (e *Err) IsMath() bool { return e.InGroup(ecdef.MATH_GROUP) }
 
 // IsNet() checks that e.Code belongs to the network group of error codes
 // defined in the ecnet package.
-func (e *Err) IsNet() bool {
-	return e.Code >= ecdef.NET_RANGE_BEGIN && e.Code <= ecdef.NET_RANGE_END
-}
+func (e *Err) IsNet() bool { return e.InGroup(ecdef.NET_GROUP) }
 
 // IsSys() checks that e.Code belongs to the system group of error codes
 // defined in the ecsys package.
-func (e *Err) IsSys() bool {
-	return e.Code >= ecdef.SYS_RANGE_BEGIN && e.Code <= ecdef.SYS_RANGE_END
-}
+func (e *Err) IsSys() bool { return e.InGroup(ecdef.SYS_GROUP) }
 
 // IsHttp() checks that e.Code belongs to the http group of error codes
 // defined in the echttp package.
-func (e *Err) IsHTTP() bool {
-	return e.Code >= ecdef.HTTP_RANGE_BEGIN && e.Code <= ecdef.HTTP_RANGE_END
-}
+func (e *Err) IsHTTP() bool { return e.InGroup(ecdef.HTTP_GROUP) }
+
+// IsHTTPClientError() checks that e.Code is an HTTP client error (4xx) status.
+func (e *Err) IsHTTPClientError() bool { return echttp.IsClientError(e.Code) }
+
+// IsHTTPServerError() checks that e.Code is an HTTP server error (5xx) status.
+func (e *Err) IsHTTPServerError() bool { return echttp.IsServerError(e.Code) }
+
+// IsHTTPRetryable() checks that e.Code is an HTTP status that indicates
+// the request may succeed if repeated later (429, 502, 503 or 504),
+// see echttp.IsRetryable().
+func (e *Err) IsHTTPRetryable() bool { return echttp.IsRetryable(e.Code) }
 
 // IsFS() checks that e.Code belongs to the file system group of error codes
 // defined in the ecfs package.
-func (e *Err) IsFS() bool {
-	return e.Code >= ecdef.FS_RANGE_BEGIN && e.Code <= ecdef.FS_RANGE_END
-}
+func (e *Err) IsFS() bool { return e.InGroup(ecdef.FS_GROUP) }
 
 // IsApp() checks that e.Code belongs to the app-specific group of error codes
 // optionally defined by user.
-func (e *Err) IsApp() bool {
-	return e.Code >= ecdef.APP_RANGE_BEGIN && e.Code <= ecdef.APP_RANGE_END
+func (e *Err) IsApp() bool { return e.InGroup(ecdef.APP_GROUP) }
+
+// InGroup() checks that e.Code belongs to the registered group of error codes
+// with specified name, see ecdef.RegisterGroup().
+// Works both for the groups defined in IGU library and for third-party ones.
+func (e *Err) InGroup(name string) bool { return ecdef.InGroup(e.Code, name) }
+
+// Name() returns the symbolic name of e.Code, e.g. "ecnet.ConnectionRefused",
+// see ecdef.CodeName().
+func (e *Err) Name() string { return ecdef.CodeName(e.Code) }
+
+// Group() returns the name of the registered group e.Code belongs to,
+// or an empty string if there is no such group.
+func (e *Err) Group() string {
+	if g, ok := ecdef.GroupOf(e.Code); ok {
+		return g.Name
+	}
+	return ""
 }
 
-// errToString(...) returns (only) current error's message as string.
+// errToString(...) returns (only) current error's message
+// and context fields as string.
 func errToString(e *Err) string {
-	var r string
-	if e.IsBasic() {
-		r = ec.ECToString(e.Code)
-	} else if e.IsFS() {
-		r = ecfs.ECToString(e.Code)
-	} else if e.IsSys() {
-		r = ecsys.ECToString(e.Code)
-	} else if e.IsMath() {
-		r = ecmath.ECToString(e.Code)
-	} else if e.IsNet() {
-		r = ecnet.ECToString(e.Code)
-	} else if e.IsApp() {
-		r = AppECToString(e.Code)
-	} else if e.IsAuth() {
-		r = ecauth.ECToString(e.Code)
-	} else if e.IsDB() {
-		r = ecdb.ECToString(e.Code)
-	} else if e.IsHTTP() {
-		r = echttp.ECToString(e.Code)
-	} else {
-		r = fmt.Sprintf("unknown error code (%d)", e.Code)
+	return errToStringWith(e, ecdef.CodeToString(e.Code))
+}
+
+// errToStringWith(...) is same as errToString()
+// but uses codeText as the description of e.Code.
+func errToStringWith(e *Err, codeText string) string {
+	r := codeText
+	if len(e.Msg) != 0 {
+		r += fmt.Sprintf(" %s", e.Msg)
 	}
-	if len(e.Msg) == 0 {
-		return r
+	if e.fields != nil && len(*e.fields) != 0 {
+		r += " " + fieldsToString(*e.fields)
 	}
-	return r + fmt.Sprintf(" %s", e.Msg)
+	return r
 }
 
 // Error() is the implementation of error interface for type Err,
@@ -169,77 +206,61 @@ func (e *Err) Eq(errCode ecdef.ErrCode) bool { return e.Code == errCode }
 // Intended for convenient traversing the error chain.
 func (e Err) Unwrap() error { return e.Cause }
 
-func compareErrorInterfaces(current error, target error) bool {
-	targetType := reflect.TypeOf(target)
-	targetValue := reflect.ValueOf(target)
-
-	currentType := reflect.TypeOf(current)
-	if currentType == targetType {
-		// Try comparing directly
-		if currentType.Comparable() {
-			currentValue := reflect.ValueOf(current)
-			return currentValue == targetValue
-			// fmt.Printf("ret false - types are same but values differ (%s, %s)\n",
-			// 	currentValue, targetValue)
-			// return false
-		}
-		// fmt.Printf("ret false - types same but  not comparable (%s, %s)\n",
-		// 	currentType, targetType)
-		return false
-	}
-	// fmt.Printf("ret false - types are different (%s, %s)\n",
-	// 	currentType, targetType)
-	return false
-}
-
 // Is() compares e and all its wrapped errors to target.
-// It returns true if e (or any of its wrapped errors) equals to target.
-// It can be used directly as a method or indirectly by errors.Is() function.
+// It returns true if e (or any of its wrapped errors) matches target,
+// it can be used directly as a method or indirectly by errors.Is() function.
+// If target is of type Err (e.g. created by Code()), errors of type Err
+// are matched by code anywhere in the chain, other targets are matched
+// the same way as errors.Is() does (by equality or by their Is() method).
 // Note that there is a much more efficient Eq() method to compare two Err object's codes.
 //	e := Err{Code: ec.Dummy, Cause: Err{Code: ec.NotFound, Cause: fs.ErrNotExist}}
-//	fmt.Println(errors.Is(e, fs.ErrNotExists)) // true
+//	fmt.Println(errors.Is(e, fs.ErrNotExist)) // true
+//	fmt.Println(errors.Is(e, Code(ec.NotFound))) // true
 func (e Err) Is(target error) bool {
 	if target == nil {
 		return false
 	}
-	var targetAsErr Err
-	targetOfTypeErr := false
-	// Special case if target is of type Err
-	if castedTarget, ok := target.(Err); ok {
-		targetAsErr = castedTarget
-		targetOfTypeErr = true
-		if e.Eq(targetAsErr.Code) {
-			return true
-		}
+	if t, ok := target.(Err); ok && e.Code == t.Code {
+		return true
 	}
-	var current error = e.Cause
-	for {
-		if current == nil {
-			return false
-		}
-		if targetOfTypeErr {
-			// check if current is also of type Err
-			if castedCurrent, ok := current.(Err); ok {
-				if castedCurrent.Eq(targetAsErr.Code) {
-					return true
-				}
-			}
-		} else {
-			if compareErrorInterfaces(current, target) {
-				return true
-			}
-		}
-		// Try to Unwrap() current cause
-		i, ok := current.(interface{ Unwrap() error })
-		if ok { // the error has Unwrap() method
-			current = i.Unwrap()
-			// fmt.Printf("successfully unwrapped current to (%s)\n",
-			// 	current)
-		} else { // can't unwrap any more
-			// fmt.Println("can't unwrap any more, returning false")
-			return false
-		}
+	return e.Cause != nil && errors.Is(e.Cause, target)
+}
+
+// Code() returns a target of errors.Is() that matches errors of type Err
+// with specified code anywhere in the chain:
+//	if errors.Is(err, Code(ec.NotFound)) { /* ... */ }
+func Code(code ecdef.ErrCode) Err { return Err{Code: code} }
+
+// As() is used by errors.As() function to find an error in the chain of e
+// that matches target. In addition to the standard behavior
+// (a target of type *Err matches e), a target of type **Err
+// is set to a pointer to a copy of e:
+//	var e *Err
+//	if errors.As(err, &e) {
+//		fmt.Println(e.Code)
+//	}
+func (e Err) As(target interface{}) bool {
+	if p, ok := target.(**Err); ok {
+		cp := e
+		*p = &cp
+		return true
+	}
+	return false
+}
+
+// CauseAs() returns the first error of type T in the chain of err
+// (err itself included) and true, or the zero value of T and false.
+// T must be an interface or implement error, see errors.As().
+//	if exitErr, ok := CauseAs[*exec.ExitError](e); ok {
+//		fmt.Println(exitErr.ExitCode())
+//	}
+func CauseAs[T any](err error) (T, bool) {
+	var target T
+	if err == nil {
+		return target, false
 	}
+	ok := errors.As(err, &target)
+	return target, ok
 }
 
 // Is() old implementation that doesn't check wrapped errors.
@@ -270,68 +291,70 @@ func AsErr(e error) (Err, bool) {
 }
 
 // FromError converts standard error interface type to Err
-// by searching for best possible match.
+// by searching for best possible match, the original error is kept as Cause.
+// The error chain is inspected in the following order:
+//	* mappers registered with RegisterErrorMapper();
+//	* context.DeadlineExceeded and context.Canceled, see ContextErrorCode();
+//	* syscall.Errno anywhere in the chain (e.g. wrapped by *os.PathError,
+//	  *os.LinkError or *os.SyscallError), see ErrnoToCode();
+//	* network errors like DNS, TLS and certificate errors, see NetErrorCode();
+//	* timeouts (errors that have Timeout() method returning true);
+//	* well-known sentinel errors like fs.ErrNotExist or io.ErrUnexpectedEOF;
+//	* *exec.ExitError.
 // If there is no match, the error code will be set to ec.Other.
 func FromError(e error) Err {
-	var code = ec.Other
-
-	// Check if it is PathError
-	if err, ok := e.(*os.PathError); ok {
-		//fmt.Printf("--- debug FromStdError() - PathError: Op: '%s', Path: '%s',"+
-		//	"Err: '%v'\n", err.Op, err.Path, err.Err)
+	return Err{Code: codeFromError(e), Cause: e}
+}
 
-		// Timeout is treaded specially in PathError
-		if err.Timeout() {
-			code = ec.TimedOut
-		} else {
-			es := err.Err.Error()
-			switch es {
-			// This seems to be MACOS-specific error
-			case "read-only file system":
-				code = ec.PermissionDenied
-			// Following are defined in package oserror
-			case "invalid argument":
-				code = ec.InvalidInput
-			case "permission denied":
-				code = ec.PermissionDenied
-			case "file already exists":
-				code = ec.AlreadyExists
-			case "file does not exist":
-				code = ec.NotFound
-			case "no such file or directory":
-				code = ec.NotFound
-			case "file already closed":
-				code = ec.AlreadyClosed
-			// Windows10-specific PermissionDenied error message
-			case "Access is denied.":
-				code = ec.PermissionDenied
-			// Windows10-specific error when it encounters a unix symlink
-			case "The name of the file cannot be resolved by the system.":
-				code = ec.SymlinksNotSupported
-			// Windows10-specific NotFound error message
-			case "The system cannot find the file specified.":
-				code = ec.NotFound
-			}
+// Sentinel errors recognized by FromError().
+var sentinelErrors = []struct {
+	err  error
+	code ecdef.ErrCode
+}{
+	{fs.ErrNotExist, ec.NotFound},
+	{fs.ErrExist, ec.AlreadyExists},
+	{fs.ErrPermission, ec.PermissionDenied},
+	{fs.ErrClosed, ec.AlreadyClosed},
+	{fs.ErrInvalid, ec.InvalidInput},
+	{os.ErrDeadlineExceeded, ec.TimedOut},
+	{io.ErrUnexpectedEOF, ec.UnexpectedEof},
+	{io.ErrClosedPipe, ec.BrokenPipe},
+	{io.ErrShortWrite, ec.WriteZero},
+	{exec.ErrNotFound, ec.NotFound},
+}
 
+func codeFromError(e error) ecdef.ErrCode {
+	if e == nil {
+		return ec.Other
+	}
+	if code, ok := codeFromMappers(e); ok {
+		return code
+	}
+	// Before NetErrorCode(): context.DeadlineExceeded is a timeout as well
+	if code, ok := ContextErrorCode(e); ok {
+		return code
+	}
+	var errno syscall.Errno
+	if errors.As(e, &errno) {
+		if code, ok := ErrnoToCode(errno); ok {
+			return code
 		}
-	} else if _, ok := e.(*os.LinkError); ok {
-		// TODO: remove this debug
-		//fmt.Printf("--- debug FromStdError() - LinkError: Op: '%s', Old: '%s', New: '%s', Err: '%v'\n", le.Op, le.Old, le.New, le.Error())
-	} else if _, ok := e.(*os.SyscallError); ok {
-		// TODO: remove this debug
-		//fmt.Printf("--- debug FromStdError() - SyscallError: Syscall: '%s', Err: '%v'\n", se.Syscall, se.Err)
-		// Timeout is treaded specially in PathError
-		if err.Timeout() {
-			code = ec.TimedOut
-		} else {
-			// es := err.Err.Error()
-			// switch es {
-			// }
-			code = ec.Other
+	}
+	if code, ok := NetErrorCode(e); ok {
+		return code
+	}
+	var timeout interface{ Timeout() bool }
+	if errors.As(e, &timeout) && timeout.Timeout() {
+		return ec.TimedOut
+	}
+	for _, s := range sentinelErrors {
+		if errors.Is(e, s.err) {
+			return s.code
 		}
-	} else if _, ok := e.(*exec.ExitError); ok {
-		code = ec.ProcessExit
 	}
-
-	return Err{Code: code, Cause: e}
+	var exitErr *exec.ExitError
+	if errors.As(e, &exitErr) {
+		return ec.ProcessExit
+	}
+	return ec.Other
 }
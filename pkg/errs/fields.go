@@ -0,0 +1,137 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Keys of the context fields used in IGU library.
+const (
+	// Path of the file system item the operation failed on.
+	KeyPath = "path"
+	// Name of the operation that failed, e.g. "copy" or "mkdir".
+	KeyOp = "op"
+	// Numeric user ID.
+	KeyUID = "uid"
+	// Numeric group ID.
+	KeyGID = "gid"
+	// User name.
+	KeyUser = "user"
+	// User group name.
+	KeyGroup = "group"
+	// URL of the remote resource.
+	KeyURL = "url"
+)
+
+// Field is a key/value pair that describes the context of an error
+// in a machine-readable way, e.g. the path of a file that can't be opened.
+// Fields are attached to an Err with With() and are kept in order.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// KV returns a context field with arbitrary key and value.
+func KV(key string, value interface{}) Field { return Field{Key: key, Value: value} }
+
+// Path returns a context field that holds a file system path.
+func Path(path string) Field { return Field{Key: KeyPath, Value: path} }
+
+// Op returns a context field that holds the name of an operation.
+func Op(op string) Field { return Field{Key: KeyOp, Value: op} }
+
+// UID returns a context field that holds a numeric user ID.
+func UID(uid int) Field { return Field{Key: KeyUID, Value: uid} }
+
+// GID returns a context field that holds a numeric group ID.
+func GID(gid int) Field { return Field{Key: KeyGID, Value: gid} }
+
+// User returns a context field that holds a user name.
+func User(name string) Field { return Field{Key: KeyUser, Value: name} }
+
+// UserGroup returns a context field that holds a user group name.
+func UserGroup(name string) Field { return Field{Key: KeyGroup, Value: name} }
+
+// URL returns a context field that holds a URL.
+func URL(url string) Field { return Field{Key: KeyURL, Value: url} }
+
+// With returns a copy of e with specified context fields attached.
+// A field replaces the previously attached one with the same key,
+// otherwise it is appended to the end. The original e is not modified.
+//
+//	return Err{Code: ec.AlreadyExists}.With(Path(path))
+func (e Err) With(fields ...Field) Err {
+	if len(fields) == 0 {
+		return e
+	}
+	var merged []Field
+	if e.fields != nil {
+		merged = make([]Field, len(*e.fields), len(*e.fields)+len(fields))
+		copy(merged, *e.fields)
+	}
+outer:
+	for _, f := range fields {
+		for i := range merged {
+			if merged[i].Key == f.Key {
+				merged[i] = f
+				continue outer
+			}
+		}
+		merged = append(merged, f)
+	}
+	e.fields = &merged
+	return e
+}
+
+// Fields returns the context fields attached to e in order,
+// the fields of its causes are not included.
+func (e Err) Fields() []Field {
+	if e.fields == nil {
+		return nil
+	}
+	r := make([]Field, len(*e.fields))
+	copy(r, *e.fields)
+	return r
+}
+
+// Field returns the value of the context field with specified key and true,
+// or nil and false if there is no such field.
+// The fields of e are checked first, then the fields of its causes,
+// so the value attached closest to the top of the chain wins.
+//
+//	if path, ok := e.Field(KeyPath); ok { ... }
+func (e Err) Field(key string) (interface{}, bool) {
+	var current error = e
+	for current != nil {
+		if casted, ok := AsErr(current); ok && casted.fields != nil {
+			for _, f := range *casted.fields {
+				if f.Key == key {
+					return f.Value, true
+				}
+			}
+		}
+		current = errors.Unwrap(current)
+	}
+	return nil, false
+}
+
+// fieldsToString formats context fields as `[key1=value1 key2=value2]`.
+func fieldsToString(fields []Field) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		v := fmt.Sprintf("%v", f.Value)
+		if v == "" || strings.ContainsAny(v, " \t\n\"=[]") {
+			v = fmt.Sprintf("%q", v)
+		}
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		b.WriteString(v)
+	}
+	b.WriteByte(']')
+	return b.String()
+}
@@ -0,0 +1,140 @@
+package errs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecdef"
+)
+
+// Localized descriptions of error codes by normalized language tag.
+var catalogMu sync.RWMutex
+var catalog = map[string]map[ecdef.ErrCode]string{}
+
+// normalizeLang converts a language tag like "de_AT" to "de-at".
+func normalizeLang(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
+
+// RegisterMessages adds localized descriptions of error codes
+// for the language with specified tag (e.g. "de" or "de-AT"),
+// replacing the previously registered descriptions of the same codes.
+func RegisterMessages(lang string, messages map[ecdef.ErrCode]string) {
+	lang = normalizeLang(lang)
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	table := catalog[lang]
+	if table == nil {
+		table = make(map[ecdef.ErrCode]string, len(messages))
+		catalog[lang] = table
+	}
+	for code, msg := range messages {
+		table[code] = msg
+	}
+}
+
+// LoadMessages loads localized descriptions of error codes from all
+// "*.json" files in directory dir of fsys, e.g. embedded files or os.DirFS().
+// The name of a file (without extension) is the language tag, e.g. "de.json".
+// Each file is a JSON object that maps symbolic names of the codes
+// (see ecdef.ParseCodeName()) or their numeric values to descriptions:
+//
+//	{
+//	  "ec.NotFound": "nicht gefunden",
+//	  "ecfs.NotADir": "kein Verzeichnis",
+//	  "1000000001": "Anwendungsfehler"
+//	}
+//
+// Possible errors:
+//
+//	ec.NotFound // dir does not exist
+//	ec.InvalidData // a file is malformed or refers to an unknown code;
+//	...or other less common errors.
+func LoadMessages(fsys fs.FS, dir string) Err {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return FromError(err).With(Path(dir))
+	}
+	if len(files) == 0 {
+		if _, err := fs.Stat(fsys, dir); err != nil {
+			return FromError(err).With(Path(dir))
+		}
+	}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return FromError(err).With(Path(file))
+		}
+		var raw map[string]string
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return Err{Code: ec.InvalidData, Cause: err}.With(Path(file))
+		}
+		messages := make(map[ecdef.ErrCode]string, len(raw))
+		for name, msg := range raw {
+			code, ok := parseCodeKey(name)
+			if !ok {
+				return Err{Code: ec.InvalidData,
+					Msg: fmt.Sprintf("unknown error code '%s'", name)}.With(Path(file))
+			}
+			messages[code] = msg
+		}
+		RegisterMessages(strings.TrimSuffix(path.Base(file), ".json"), messages)
+	}
+	return NoError
+}
+
+// parseCodeKey parses a symbolic name or a numeric value of an error code.
+func parseCodeKey(key string) (ecdef.ErrCode, bool) {
+	if n, err := strconv.ParseInt(key, 10, 32); err == nil {
+		return ecdef.ErrCode(n), true
+	}
+	return ecdef.ParseCodeName(key)
+}
+
+// LocalizedCodeString returns the description of code in the language
+// with specified tag. If there is no description for the tag
+// (e.g. "de-at"), its base language ("de") is tried,
+// then the default English description is returned, see ecdef.CodeToString().
+func LocalizedCodeString(code ecdef.ErrCode, lang string) string {
+	lang = normalizeLang(lang)
+	catalogMu.RLock()
+	for lang != "" {
+		if msg, ok := catalog[lang][code]; ok {
+			catalogMu.RUnlock()
+			return msg
+		}
+		i := strings.LastIndexByte(lang, '-')
+		if i < 0 {
+			break
+		}
+		lang = lang[:i]
+	}
+	catalogMu.RUnlock()
+	return ecdef.CodeToString(code)
+}
+
+// LocalizedError() is same as Error() but the descriptions of error codes
+// in the whole chain are rendered in the language with specified tag,
+// see LocalizedCodeString(). Messages and context fields are not translated.
+// Wrapped errors of other types are rendered with their LocalizedError()
+// method if they have one (like *MultiErr) or with Error() otherwise.
+func (e Err) LocalizedError(lang string) string {
+	r := errToStringWith(&e, LocalizedCodeString(e.Code, lang))
+	for cause := e.Cause; cause != nil; cause = errors.Unwrap(cause) {
+		if casted, ok := AsErr(cause); ok {
+			r += ": " + errToStringWith(&casted, LocalizedCodeString(casted.Code, lang))
+		} else if l, ok := cause.(interface{ LocalizedError(string) string }); ok {
+			r += ": " + l.LocalizedError(lang)
+		} else {
+			r += fmt.Sprintf(": %s", cause)
+		}
+	}
+	return r
+}
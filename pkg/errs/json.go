@@ -0,0 +1,260 @@
+package errs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecdef"
+)
+
+// WireErr is the stable wire representation of an error chain,
+// used to send errors across process boundaries.
+// Every link of the chain is represented by a WireErr:
+// a link of type Err fills Code, Name, Group, Msg and Context,
+// any other error is kept as opaque text in Text.
+// The errors wrapped by a link with several causes (Unwrap() []error)
+// are kept in Errors.
+// The JSON form of an Err chain looks like:
+//
+//	{
+//	  "code": 1,
+//	  "name": "ec.NotFound",
+//	  "group": "ec",
+//	  "msg": "config file",
+//	  "context": [{"key": "path", "value": "/etc/app.conf"}],
+//	  "cause": {"text": "open /etc/app.conf: no such file or directory"}
+//	}
+//
+// Name and Group are informational, only Code is used when decoding.
+// Call stacks are not transferred.
+type WireErr struct {
+	// Opaque is true if this link is not of type Err;
+	// in JSON, opaque links are the ones without "code".
+	Opaque bool
+	// Error code.
+	Code ecdef.ErrCode
+	// Symbolic name of the code, e.g. "ec.NotFound".
+	Name string
+	// Name of the group the code belongs to, e.g. "ec".
+	Group string
+	// Err.Msg
+	Msg string
+	// Context fields in order.
+	Context []WireField
+	// Error text of a link that is not of type Err.
+	Text string
+	// The next link of the chain (optional).
+	Cause *WireErr
+	// Errors wrapped by a link with several causes (optional).
+	Errors []WireErr
+}
+
+// wireErrJSON defines the JSON layout of WireErr.
+type wireErrJSON struct {
+	Code    *ecdef.ErrCode `json:"code,omitempty"`
+	Name    string         `json:"name,omitempty"`
+	Group   string         `json:"group,omitempty"`
+	Msg     string         `json:"msg,omitempty"`
+	Context []WireField    `json:"context,omitempty"`
+	Text    string         `json:"text,omitempty"`
+	Cause   *WireErr       `json:"cause,omitempty"`
+	Errors  []WireErr      `json:"errors,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (w WireErr) MarshalJSON() ([]byte, error) {
+	j := wireErrJSON{
+		Name:    w.Name,
+		Group:   w.Group,
+		Msg:     w.Msg,
+		Context: w.Context,
+		Text:    w.Text,
+		Cause:   w.Cause,
+		Errors:  w.Errors,
+	}
+	if !w.Opaque {
+		code := w.Code
+		j.Code = &code
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (w *WireErr) UnmarshalJSON(data []byte) error {
+	var j wireErrJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*w = WireErr{
+		Opaque:  j.Code == nil,
+		Name:    j.Name,
+		Group:   j.Group,
+		Msg:     j.Msg,
+		Context: j.Context,
+		Text:    j.Text,
+		Cause:   j.Cause,
+		Errors:  j.Errors,
+	}
+	if j.Code != nil {
+		w.Code = *j.Code
+	}
+	return nil
+}
+
+// WireField is the wire representation of a context field.
+type WireField struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// OpaqueError represents an error of unknown type decoded from its
+// wire representation; only its text and its cause (if any) are preserved.
+// Errors of an error with several causes are restored into Errors.
+type OpaqueError struct {
+	Text   string
+	Cause  error
+	Errors []error
+}
+
+func (e *OpaqueError) Error() string { return e.Text }
+
+// Unwrap() returns the wrapped error if any or nil otherwise.
+func (e *OpaqueError) Unwrap() error { return e.Cause }
+
+// Is() returns true if any of e.Errors matches target, see errors.Is().
+func (e *OpaqueError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As() finds the first of e.Errors that matches target, see errors.As().
+func (e *OpaqueError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ToWire converts the chain of err into its wire representation,
+// returns nil if err is nil.
+func ToWire(err error) *WireErr {
+	if err == nil {
+		return nil
+	}
+	var w WireErr
+	if e, ok := AsErr(err); ok {
+		w.Code = e.Code
+		w.Name = ecdef.CodeName(e.Code)
+		w.Group = e.Group()
+		w.Msg = e.Msg
+		for _, f := range e.Fields() {
+			w.Context = append(w.Context, WireField(f))
+		}
+	} else {
+		w.Text = err.Error()
+		w.Opaque = true
+	}
+	if multi, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, member := range multi.Unwrap() {
+			if member != nil {
+				w.Errors = append(w.Errors, *ToWire(member))
+			}
+		}
+	} else {
+		w.Cause = ToWire(errors.Unwrap(err))
+	}
+	return &w
+}
+
+// FromWire restores an error chain from its wire representation.
+// Links of type Err are restored as Err, other links as *OpaqueError;
+// returns nil if w is nil.
+func FromWire(w *WireErr) error {
+	if w == nil {
+		return nil
+	}
+	if w.Opaque {
+		opaque := &OpaqueError{Text: w.Text, Cause: FromWire(w.Cause)}
+		for i := range w.Errors {
+			opaque.Errors = append(opaque.Errors, FromWire(&w.Errors[i]))
+		}
+		return opaque
+	}
+	return w.toErr()
+}
+
+// toErr restores w as Err, an opaque w is restored as Err with ec.Other code.
+func (w *WireErr) toErr() Err {
+	if w.Opaque {
+		return Err{Code: ec.Other, Cause: FromWire(w)}
+	}
+	e := Err{Code: w.Code, Msg: w.Msg, Cause: FromWire(w.Cause)}
+	for _, f := range w.Context {
+		e = e.With(Field(f))
+	}
+	return e
+}
+
+// MarshalJSON implements json.Marshaler,
+// see WireErr for the description of the format.
+func (e Err) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ToWire(e))
+}
+
+// UnmarshalJSON implements json.Unmarshaler,
+// see WireErr for the description of the format.
+// If the top-level link is opaque, it is wrapped into Err with ec.Other code.
+// The restored Err can be checked with Eq(), Is(), IsFS() etc.
+// as the original one.
+func (e *Err) UnmarshalJSON(data []byte) error {
+	var w WireErr
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	*e = w.toErr()
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Integer values are decoded as int (or int64 if they do not fit),
+// other numbers as float64.
+func (f *WireField) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Key   string          `json:"key"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	f.Key = raw.Key
+	f.Value = nil
+	if len(raw.Value) == 0 {
+		return nil
+	}
+	d := json.NewDecoder(bytes.NewReader(raw.Value))
+	d.UseNumber()
+	if err := d.Decode(&f.Value); err != nil {
+		return err
+	}
+	if n, ok := f.Value.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			if i >= math.MinInt && i <= math.MaxInt {
+				f.Value = int(i)
+			} else {
+				f.Value = i
+			}
+		} else if fl, err := n.Float64(); err == nil {
+			f.Value = fl
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package errs
+
+import (
+	"os/exec"
+	"sync"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecauth"
+	"github.com/iotanbo/igu/pkg/ecdef"
+	"github.com/iotanbo/igu/pkg/ecfs"
+	"github.com/iotanbo/igu/pkg/ecnet"
+	"github.com/iotanbo/igu/pkg/ecsys"
+)
+
+// Process exit codes defined in sysexits.h.
+const (
+	EX_OK          = 0
+	EX_USAGE       = 64
+	EX_DATAERR     = 65
+	EX_NOINPUT     = 66
+	EX_NOUSER      = 67
+	EX_NOHOST      = 68
+	EX_UNAVAILABLE = 69
+	EX_SOFTWARE    = 70
+	EX_OSERR       = 71
+	EX_OSFILE      = 72
+	EX_CANTCREAT   = 73
+	EX_IOERR       = 74
+	EX_TEMPFAIL    = 75
+	EX_PROTOCOL    = 76
+	EX_NOPERM      = 77
+	EX_CONFIG      = 78
+)
+
+// Default results of HTTPStatus() and ExitCode()
+// for the codes that are not mapped explicitly.
+const (
+	DEFAULT_HTTP_STATUS = 500
+	DEFAULT_EXIT_CODE   = 1
+)
+
+// mappingTable maps error codes and groups of error codes to integers.
+type mappingTable struct {
+	codes  map[ecdef.ErrCode]int
+	groups map[string]int
+}
+
+var mappingMu sync.RWMutex
+
+var httpStatusTable = mappingTable{
+	codes: map[ecdef.ErrCode]int{
+		ec.NoError:                 200,
+		ec.NotFound:                404,
+		ec.PermissionDenied:        403,
+		ec.AlreadyExists:           409,
+		ec.InvalidInput:            400,
+		ec.InvalidData:             400,
+		ec.Syntax:                  400,
+		ec.Type:                    400,
+		ec.Value:                   400,
+		ec.Key:                     400,
+		ec.Index:                   400,
+		ec.TimedOut:                504,
+		ec.WouldBlock:              503,
+		ec.Interrupted:             503,
+		ec.DeadlineExceeded:        504,
+		ec.NotImplemented:          501,
+		ec.Unsupported:             501,
+		ecauth.Failed:              401,
+		ecauth.Credentials:         401,
+		ecauth.UnsupportedMethod:   401,
+		ecfs.NoSpace:               507,
+		ecfs.QuotaExceeded:         507,
+		ecfs.InvalidPath:           400,
+		ecfs.NameTooLong:           400,
+		ecnet.DNSFailure:           502,
+		ecnet.UntrustedCertificate: 502,
+	},
+	groups: map[string]int{
+		ecdef.NET_GROUP: 502,
+	},
+}
+
+var exitCodeTable = mappingTable{
+	codes: map[ecdef.ErrCode]int{
+		ec.NoError:               EX_OK,
+		ec.NotFound:              2,
+		ec.PermissionDenied:      EX_NOPERM,
+		ec.AlreadyExists:         EX_CANTCREAT,
+		ec.InvalidInput:          EX_USAGE,
+		ec.InvalidData:           EX_DATAERR,
+		ec.Syntax:                EX_DATAERR,
+		ec.UnexpectedEof:         EX_DATAERR,
+		ec.TimedOut:              EX_TEMPFAIL,
+		ec.WouldBlock:            EX_TEMPFAIL,
+		ec.Interrupted:           130,
+		ec.Canceled:              130,
+		ec.DeadlineExceeded:      EX_TEMPFAIL,
+		ec.NotImplemented:        EX_UNAVAILABLE,
+		ec.Unsupported:           EX_UNAVAILABLE,
+		ec.Memory:                EX_OSERR,
+		ec.Assertion:             EX_SOFTWARE,
+		ec.Panic:                 EX_SOFTWARE,
+		ecsys.KeyboardInterrupt:  130,
+		ecsys.Error:              EX_OSERR,
+		ecnet.DNSFailure:         EX_NOHOST,
+		ecnet.HostUnreachable:    EX_NOHOST,
+		ecnet.NetworkUnreachable: EX_NOHOST,
+	},
+	groups: map[string]int{
+		ecdef.FS_GROUP:   EX_IOERR,
+		ecdef.NET_GROUP:  EX_UNAVAILABLE,
+		ecdef.AUTH_GROUP: EX_NOPERM,
+		ecdef.DB_GROUP:   EX_UNAVAILABLE,
+		ecdef.HTTP_GROUP: EX_PROTOCOL,
+	},
+}
+
+func (t *mappingTable) lookup(code ecdef.ErrCode) (int, bool) {
+	mappingMu.RLock()
+	defer mappingMu.RUnlock()
+	if v, ok := t.codes[code]; ok {
+		return v, true
+	}
+	if g, ok := ecdef.GroupOf(code); ok {
+		if v, ok := t.groups[g.Name]; ok {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// RegisterHTTPStatus makes HTTPStatus() return status for code,
+// overriding the default mapping if any.
+func RegisterHTTPStatus(code ecdef.ErrCode, status int) {
+	mappingMu.Lock()
+	defer mappingMu.Unlock()
+	httpStatusTable.codes[code] = status
+}
+
+// RegisterGroupHTTPStatus makes HTTPStatus() return status for all codes
+// of the group with specified name that are not mapped individually.
+func RegisterGroupHTTPStatus(group string, status int) {
+	mappingMu.Lock()
+	defer mappingMu.Unlock()
+	httpStatusTable.groups[group] = status
+}
+
+// RegisterExitCode makes ExitCode() return exitCode for code,
+// overriding the default mapping if any.
+func RegisterExitCode(code ecdef.ErrCode, exitCode int) {
+	mappingMu.Lock()
+	defer mappingMu.Unlock()
+	exitCodeTable.codes[code] = exitCode
+}
+
+// RegisterGroupExitCode makes ExitCode() return exitCode for all codes
+// of the group with specified name that are not mapped individually.
+func RegisterGroupExitCode(group string, exitCode int) {
+	mappingMu.Lock()
+	defer mappingMu.Unlock()
+	exitCodeTable.groups[group] = exitCode
+}
+
+// HTTPStatus() returns the HTTP status code that corresponds to e.Code,
+// e.g. 404 for ec.NotFound. Codes of the echttp group are mapped
+// to themselves, unknown codes to DEFAULT_HTTP_STATUS (500).
+// The mapping can be changed with RegisterHTTPStatus() and
+// RegisterGroupHTTPStatus().
+func (e *Err) HTTPStatus() int {
+	if status, ok := httpStatusTable.lookup(e.Code); ok {
+		return status
+	}
+	if e.IsHTTP() {
+		return int(e.Code)
+	}
+	return DEFAULT_HTTP_STATUS
+}
+
+// ExitCode() returns the process exit code that corresponds to e.Code,
+// e.g. 2 for ec.NotFound or EX_NOPERM (77) for ec.PermissionDenied,
+// see sysexits.h. For ec.ProcessExit the exit code of the sub-process
+// is returned if the chain contains *exec.ExitError. Unknown codes
+// are mapped to DEFAULT_EXIT_CODE (1). The mapping can be changed
+// with RegisterExitCode() and RegisterGroupExitCode().
+func (e *Err) ExitCode() int {
+	if e.Code == ec.ProcessExit {
+		if exitErr, ok := CauseAs[*exec.ExitError](e.Cause); ok && exitErr.ExitCode() > 0 {
+			return exitErr.ExitCode()
+		}
+	}
+	if exitCode, ok := exitCodeTable.lookup(e.Code); ok {
+		return exitCode
+	}
+	return DEFAULT_EXIT_CODE
+}
@@ -0,0 +1,185 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecdef"
+)
+
+// MultiItem is a single failure collected by MultiErr.
+type MultiItem struct {
+	// Index of the failed item in the batch, -1 if the item is identified by Key.
+	Index int
+	// Key of the failed item, e.g. a file path (optional).
+	Key string
+	// The error of the item.
+	Err error
+}
+
+// MultiErr collects errors of a batch operation (e.g. copying many files)
+// with the indices or keys of the failed items.
+// The zero value is an empty MultiErr ready to use:
+//
+//	var me MultiErr
+//	for i, path := range paths {
+//		me.AddAt(i, process(path))
+//	}
+//	return me.ToErr() // NoError if nothing failed
+//
+// errors.Is() and errors.As() match any of the collected errors.
+type MultiErr struct {
+	// Overall error code, see OverallCode() (optional).
+	Code ecdef.ErrCode
+	// Optional message, e.g. the name of the batch operation.
+	Msg string
+	// Collected errors in order of addition.
+	Items []MultiItem
+}
+
+// Add appends err with the index equal to the number of items added so far.
+// Nil errors and Err values without error are ignored.
+func (m *MultiErr) Add(err error) {
+	m.AddAt(len(m.Items), err)
+}
+
+// AddAt appends err as the error of the item with specified index.
+// Nil errors and Err values without error are ignored.
+func (m *MultiErr) AddAt(index int, err error) {
+	if isNoError(err) {
+		return
+	}
+	m.Items = append(m.Items, MultiItem{Index: index, Err: err})
+}
+
+// AddKey appends err as the error of the item with specified key.
+// Nil errors and Err values without error are ignored.
+func (m *MultiErr) AddKey(key string, err error) {
+	if isNoError(err) {
+		return
+	}
+	m.Items = append(m.Items, MultiItem{Index: -1, Key: key, Err: err})
+}
+
+func isNoError(err error) bool {
+	if err == nil {
+		return true
+	}
+	e, ok := err.(Err)
+	return ok && e.None()
+}
+
+// Some() returns true if at least one error was collected.
+func (m *MultiErr) Some() bool { return len(m.Items) != 0 }
+
+// None() returns true if no errors were collected.
+func (m *MultiErr) None() bool { return len(m.Items) == 0 }
+
+// Len() returns the number of collected errors.
+func (m *MultiErr) Len() int { return len(m.Items) }
+
+// Errors() returns the collected errors in order of addition.
+func (m *MultiErr) Errors() []error {
+	r := make([]error, len(m.Items))
+	for i := range m.Items {
+		r[i] = m.Items[i].Err
+	}
+	return r
+}
+
+// OverallCode() returns m.Code if set; otherwise the code shared
+// by all collected errors if they are of type Err and have the same code,
+// or ec.Multiple. Returns ec.NoError if no errors were collected.
+func (m *MultiErr) OverallCode() ecdef.ErrCode {
+	if m.None() {
+		return ec.NoError
+	}
+	if m.Code != ec.NoError {
+		return m.Code
+	}
+	var code ecdef.ErrCode
+	for i, item := range m.Items {
+		e, ok := item.Err.(Err)
+		if !ok || (i > 0 && e.Code != code) {
+			return ec.Multiple
+		}
+		code = e.Code
+	}
+	return code
+}
+
+// ToErr() returns NoError if no errors were collected,
+// otherwise Err with the overall code (see OverallCode()), m.Msg
+// and a copy of m as Cause, so that the result fits the Some()/None() idiom.
+func (m *MultiErr) ToErr() Err {
+	if m.None() {
+		return NoError
+	}
+	cp := &MultiErr{Code: m.Code, Msg: m.Msg, Items: make([]MultiItem, len(m.Items))}
+	copy(cp.Items, m.Items)
+	return Err{Code: m.OverallCode(), Msg: m.Msg, Cause: cp}
+}
+
+// Error() returns a one-line summary of the collected errors, e.g.
+//
+//	2 errors: [0] ec.NotFound; [/tmp/a] ec.PermissionDenied
+//
+// The overall code and message are reported by the Err returned from ToErr().
+func (m *MultiErr) Error() string {
+	return m.summary(func(err error) string { return err.Error() })
+}
+
+// LocalizedError() is same as Error() but the collected errors of type Err
+// are rendered in the language with specified tag, see Err.LocalizedError().
+func (m *MultiErr) LocalizedError(lang string) string {
+	return m.summary(func(err error) string {
+		if l, ok := err.(interface{ LocalizedError(string) string }); ok {
+			return l.LocalizedError(lang)
+		}
+		return err.Error()
+	})
+}
+
+func (m *MultiErr) summary(toString func(error) string) string {
+	parts := make([]string, len(m.Items))
+	for i, item := range m.Items {
+		if item.Index < 0 {
+			parts[i] = fmt.Sprintf("[%s] %s", item.Key, toString(item.Err))
+		} else {
+			parts[i] = fmt.Sprintf("[%d] %s", item.Index, toString(item.Err))
+		}
+	}
+	noun := "errors"
+	if len(m.Items) == 1 {
+		noun = "error"
+	}
+	return fmt.Sprintf("%d %s: %s", len(m.Items), noun, strings.Join(parts, "; "))
+}
+
+// Unwrap() returns the collected errors, it is used by errors.Is()
+// and errors.As() of Go 1.20 and newer.
+func (m *MultiErr) Unwrap() []error { return m.Errors() }
+
+// Is() returns true if any of the collected errors matches target,
+// see errors.Is().
+func (m *MultiErr) Is(target error) bool {
+	for _, item := range m.Items {
+		if errors.Is(item.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As() finds the first collected error that matches target,
+// see errors.As().
+func (m *MultiErr) As(target interface{}) bool {
+	for _, item := range m.Items {
+		if errors.As(item.Err, target) {
+			return true
+		}
+	}
+	return false
+}
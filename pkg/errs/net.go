@@ -0,0 +1,78 @@
+package errs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecdef"
+	"github.com/iotanbo/igu/pkg/ecnet"
+)
+
+// NetErrorCode classifies network-related errors found anywhere in the chain
+// of err (e.g. wrapped by *url.Error returned from http.Client):
+//   - timeouts -> ec.TimedOut;
+//   - *net.DNSError -> ecnet.DNSFailure;
+//   - x509.UnknownAuthorityError -> ecnet.UntrustedCertificate;
+//   - x509.HostnameError -> ecnet.HostnameMismatch;
+//   - x509.CertificateInvalidError -> ecnet.InvalidCertificate;
+//   - tls.RecordHeaderError and TLS alerts -> ecnet.TLSHandshake;
+//   - *net.AddrError -> ecnet.InvalidAddress;
+//   - net.UnknownNetworkError -> ec.Unsupported;
+//   - net.ErrClosed -> ec.AlreadyClosed;
+//   - any other *net.OpError -> ecnet.Error.
+//
+// Returns ec.Other and false if err is not a network error.
+// Errors caused by syscall.Errno (e.g. "connection refused") are classified
+// by FromError() with ErrnoToCode() before this function is consulted.
+func NetErrorCode(err error) (ecdef.ErrCode, bool) {
+	if err == nil {
+		return ec.Other, false
+	}
+	var timeout interface{ Timeout() bool }
+	if errors.As(err, &timeout) && timeout.Timeout() {
+		return ec.TimedOut, true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return ecnet.DNSFailure, true
+	}
+	var unknownAuthErr x509.UnknownAuthorityError
+	if errors.As(err, &unknownAuthErr) {
+		return ecnet.UntrustedCertificate, true
+	}
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &hostnameErr) {
+		return ecnet.HostnameMismatch, true
+	}
+	var certErr x509.CertificateInvalidError
+	if errors.As(err, &certErr) {
+		return ecnet.InvalidCertificate, true
+	}
+	var recordErr tls.RecordHeaderError
+	if errors.As(err, &recordErr) {
+		return ecnet.TLSHandshake, true
+	}
+	var addrErr *net.AddrError
+	if errors.As(err, &addrErr) {
+		return ecnet.InvalidAddress, true
+	}
+	var unknownNetErr net.UnknownNetworkError
+	if errors.As(err, &unknownNetErr) {
+		return ec.Unsupported, true
+	}
+	if errors.Is(err, net.ErrClosed) {
+		return ec.AlreadyClosed, true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		// TLS alerts sent by the peer are reported with this operation name
+		if opErr.Op == "remote error" {
+			return ecnet.TLSHandshake, true
+		}
+		return ecnet.Error, true
+	}
+	return ec.Other, false
+}
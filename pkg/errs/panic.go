@@ -0,0 +1,71 @@
+package errs
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/iotanbo/igu/pkg/ec"
+)
+
+// PanicError holds a recovered panic value and the call stack
+// of the panicking goroutine, see Recover().
+type PanicError struct {
+	// The value passed to panic().
+	Value interface{}
+	// Call stack at the moment of recovery, as formatted by debug.Stack().
+	Stack []byte
+}
+
+// Error returns the panic value as text,
+// so that the whole Err reads like
+// "ec.Panic (recovered panic): index out of range".
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("%v", p.Value)
+}
+
+// Unwrap returns the panic value if it is an error, so that errors.Is()
+// and errors.As() can inspect errors passed to panic().
+func (p *PanicError) Unwrap() error {
+	if err, ok := p.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Recover converts a panic of the current goroutine into Err with code
+// ec.Panic and assigns it to *e; the panic value and the call stack
+// are kept as *PanicError in Cause. It does nothing if there is no panic.
+// Recover must be deferred directly, typically with a named return value:
+//
+//	func Parse(data []byte) (r Result, e Err) {
+//		defer Recover(&e)
+//		...
+//	}
+func Recover(e *Err) {
+	if r := recover(); r != nil {
+		*e = Err{Code: ec.Panic, Cause: &PanicError{Value: r, Stack: debug.Stack()}}
+	}
+}
+
+// Go runs f in a new goroutine and returns a channel that receives
+// the result of f, or Err with code ec.Panic if f panics (see Recover()).
+// The channel is buffered and closed after the result is sent,
+// so the goroutine never blocks even if the result is not received:
+//
+//	done := Go(func() Err { return process(items) })
+//	...
+//	if e := <-done; e.Some() { /* handle errors */ }
+func Go(f func() Err) <-chan Err {
+	ch := make(chan Err, 1)
+	go func() {
+		defer close(ch)
+		ch <- callRecover(f)
+	}()
+	return ch
+}
+
+// callRecover calls f and converts its panic into Err.
+func callRecover(f func() Err) (e Err) {
+	defer Recover(&e)
+	return f()
+}
@@ -0,0 +1,156 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync/atomic"
+
+	"github.com/iotanbo/igu/pkg/ecdef"
+)
+
+// Maximum number of stack frames recorded for an Err.
+const maxStackDepth = 32
+
+// stack holds program counters of the call stack
+// captured at the moment an Err was created.
+type stack []uintptr
+
+// Non-zero if stack capture is globally enabled.
+var stackCaptureEnabled int32
+
+// SetStackCapture globally enables or disables recording of the call stack
+// by New() and Wrap(). It is disabled by default so that creating errors
+// remains cheap; it can be switched on e.g. in debug builds
+// or while investigating an issue.
+// Err objects created as struct literals never record the call stack.
+func SetStackCapture(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&stackCaptureEnabled, v)
+}
+
+// StackCaptureEnabled returns true if stack capture is globally enabled.
+func StackCaptureEnabled() bool {
+	return atomic.LoadInt32(&stackCaptureEnabled) != 0
+}
+
+// callers records the call stack skipping `skip` frames above its caller.
+func callers(skip int) *stack {
+	var pcs [maxStackDepth]uintptr
+	// Skip runtime.Callers and callers itself
+	n := runtime.Callers(skip+2, pcs[:])
+	s := make(stack, n)
+	copy(s, pcs[:n])
+	return &s
+}
+
+// joinMsg joins optional message parts with spaces.
+func joinMsg(msg []string) string {
+	switch len(msg) {
+	case 0:
+		return ""
+	case 1:
+		return msg[0]
+	}
+	return strings.Join(msg, " ")
+}
+
+// New creates an Err with specified code and optional message.
+// If stack capture is enabled with SetStackCapture(true),
+// the call stack is recorded, otherwise the result
+// is same as Err{Code: code, Msg: msg}.
+//
+//	e := New(ec.NotFound, "config file")
+func New(code ecdef.ErrCode, msg ...string) Err {
+	if StackCaptureEnabled() {
+		return Err{Code: code, Msg: joinMsg(msg), stack: callers(1)}
+	}
+	return Err{Code: code, Msg: joinMsg(msg)}
+}
+
+// Wrap creates an Err with specified code and optional message
+// that wraps cause.
+// If stack capture is enabled with SetStackCapture(true),
+// the call stack is recorded.
+//
+//	e := Wrap(err, ec.Other, "can't read config")
+func Wrap(cause error, code ecdef.ErrCode, msg ...string) Err {
+	if StackCaptureEnabled() {
+		return Err{Code: code, Msg: joinMsg(msg), Cause: cause, stack: callers(1)}
+	}
+	return Err{Code: code, Msg: joinMsg(msg), Cause: cause}
+}
+
+// WithStack returns a copy of e with the call stack of the caller recorded,
+// regardless of whether stack capture is globally enabled.
+// Allows capturing the stack for a single call:
+//
+//	return Err{Code: ec.NotFound, Msg: path}.WithStack()
+func (e Err) WithStack() Err {
+	e.stack = callers(1)
+	return e
+}
+
+// StackTrace returns the frames of the call stack recorded
+// when e was created, or nil if the stack was not recorded.
+// Only e itself is inspected, not its causes.
+func (e Err) StackTrace() []runtime.Frame {
+	if e.stack == nil || len(*e.stack) == 0 {
+		return nil
+	}
+	var r []runtime.Frame
+	frames := runtime.CallersFrames(*e.stack)
+	for {
+		f, more := frames.Next()
+		r = append(r, f)
+		if !more {
+			break
+		}
+	}
+	return r
+}
+
+// Format implements fmt.Formatter.
+// Verbs %s and %v print same as Error(), %q prints it quoted,
+// %+v prints each error of the Cause chain on a separate line
+// followed by the frames of its recorded call stack (if any).
+func (e Err) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			writeVerbose(s, e)
+			return
+		}
+		io.WriteString(s, e.Error())
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(errs.Err=%s)", verb, e.Error())
+	}
+}
+
+// writeVerbose writes the whole Cause chain of e with stack frames.
+func writeVerbose(w io.Writer, e Err) {
+	current := error(e)
+	for i := 0; current != nil; i++ {
+		if i > 0 {
+			io.WriteString(w, "\ncaused by: ")
+		}
+		if casted, ok := AsErr(current); ok {
+			io.WriteString(w, errToString(&casted))
+			for _, f := range casted.StackTrace() {
+				fmt.Fprintf(w, "\n\t%s\n\t\t%s:%d", f.Function, f.File, f.Line)
+			}
+		} else {
+			io.WriteString(w, current.Error())
+		}
+		current = errors.Unwrap(current)
+	}
+}
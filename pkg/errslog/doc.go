@@ -0,0 +1,27 @@
+//go:build go1.21
+
+/*
+Package errslog integrates errs.Err with structured logging of log/slog.
+Instead of collapsing into the Error() string, a logged Err becomes
+a group of attributes:
+
+	code    // numeric error code
+	name    // symbolic name, e.g. "ecnet.ConnectionRefused"
+	group   // name of the code group, e.g. "ecnet"
+	msg     // message (if any)
+	fields  // context fields attached with Err.With() (if any)
+	source  // location where the error was created (if the stack was recorded)
+	cause   // the cause as a nested group, or its text for other errors
+	errors  // collected errors of errs.MultiErr, keyed by index or key
+
+Usage example:
+
+	logger.Error("download failed", errslog.Attr(e))
+	// or convert all errors automatically:
+	h := slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
+		ReplaceAttr: errslog.ReplaceAttr,
+	})
+
+The package requires Go 1.21 or newer.
+*/
+package errslog
@@ -0,0 +1,137 @@
+//go:build go1.21
+
+package errslog
+
+import (
+	"log/slog"
+	"strconv"
+
+	"github.com/iotanbo/igu/pkg/errs"
+)
+
+// Default key of the attribute created by Attr().
+const KEY = "error"
+
+// Keys of the attributes that describe an error.
+const (
+	KEY_CODE   = "code"
+	KEY_NAME   = "name"
+	KEY_GROUP  = "group"
+	KEY_MSG    = "msg"
+	KEY_FIELDS = "fields"
+	KEY_SOURCE = "source"
+	KEY_CAUSE  = "cause"
+	KEY_ERRORS = "errors"
+)
+
+// Maximum depth of nested causes, deeper causes are rendered as text.
+const MAX_DEPTH = 32
+
+// Valuer implements slog.LogValuer for an error.
+type Valuer struct {
+	Err error
+}
+
+// LogValue implements slog.LogValuer, see Value().
+func (v Valuer) LogValue() slog.Value { return Value(v.Err) }
+
+// Of returns err as slog.LogValuer, so that it is logged
+// as a group of attributes (see Value()) by any slog handler:
+//
+//	logger.Warn("retrying", "error", errslog.Of(e))
+func Of(err error) slog.LogValuer { return Valuer{Err: err} }
+
+// Attr returns an attribute with key "error" that holds err
+// as a group of attributes, see Value().
+func Attr(err error) slog.Attr {
+	return slog.Any(KEY, Of(err))
+}
+
+// Value converts err into a slog group value with the code,
+// symbolic name, group, message, context fields, source location
+// and nested causes of an Err (see the package documentation).
+// Errors of type *errs.MultiErr are rendered with their collected errors,
+// other errors are rendered as a string value.
+func Value(err error) slog.Value {
+	return value(err, 0)
+}
+
+func value(err error, depth int) slog.Value {
+	if err == nil {
+		return slog.StringValue("<nil>")
+	}
+	if depth >= MAX_DEPTH {
+		return slog.StringValue(err.Error())
+	}
+	switch e := err.(type) {
+	case errs.Err:
+		return errValue(e, depth)
+	case *errs.MultiErr:
+		return multiValue(e, depth)
+	}
+	return slog.StringValue(err.Error())
+}
+
+func errValue(e errs.Err, depth int) slog.Value {
+	attrs := []slog.Attr{
+		slog.Int64(KEY_CODE, int64(e.Code)),
+		slog.String(KEY_NAME, e.Name()),
+	}
+	if group := e.Group(); group != "" {
+		attrs = append(attrs, slog.String(KEY_GROUP, group))
+	}
+	if e.Msg != "" {
+		attrs = append(attrs, slog.String(KEY_MSG, e.Msg))
+	}
+	if fields := e.Fields(); len(fields) != 0 {
+		fieldAttrs := make([]slog.Attr, len(fields))
+		for i, f := range fields {
+			fieldAttrs[i] = slog.Any(f.Key, f.Value)
+		}
+		attrs = append(attrs, slog.Attr{Key: KEY_FIELDS, Value: slog.GroupValue(fieldAttrs...)})
+	}
+	if frames := e.StackTrace(); len(frames) != 0 {
+		attrs = append(attrs, slog.Any(KEY_SOURCE, &slog.Source{
+			Function: frames[0].Function,
+			File:     frames[0].File,
+			Line:     frames[0].Line,
+		}))
+	}
+	if e.Cause != nil {
+		attrs = append(attrs, slog.Attr{Key: KEY_CAUSE, Value: value(e.Cause, depth+1)})
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func multiValue(m *errs.MultiErr, depth int) slog.Value {
+	items := make([]slog.Attr, len(m.Items))
+	for i, item := range m.Items {
+		key := item.Key
+		if item.Index >= 0 {
+			key = strconv.Itoa(item.Index)
+		}
+		items[i] = slog.Attr{Key: key, Value: value(item.Err, depth+1)}
+	}
+	attrs := []slog.Attr{slog.Int64(KEY_CODE, int64(m.OverallCode()))}
+	if m.Msg != "" {
+		attrs = append(attrs, slog.String(KEY_MSG, m.Msg))
+	}
+	attrs = append(attrs, slog.Attr{Key: KEY_ERRORS, Value: slog.GroupValue(items...)})
+	return slog.GroupValue(attrs...)
+}
+
+// ReplaceAttr can be used as slog.HandlerOptions.ReplaceAttr
+// to log all attributes that hold an errs.Err or *errs.MultiErr
+// as groups of attributes, see Value(). Other attributes are not changed.
+func ReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() != slog.KindAny {
+		return a
+	}
+	switch err := a.Value.Any().(type) {
+	case errs.Err:
+		a.Value = Value(err)
+	case *errs.MultiErr:
+		a.Value = Value(err)
+	}
+	return a
+}
@@ -0,0 +1,74 @@
+//go:build go1.21
+
+package errslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecfs"
+	//lint:ignore ST1001 - for concise error handling.
+	. "github.com/iotanbo/igu/pkg/errs"
+	"github.com/stretchr/testify/require"
+)
+
+// logJSON logs err with a JSON handler and returns the decoded record.
+func logJSON(t *testing.T, opts *slog.HandlerOptions, args ...any) map[string]any {
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, opts)).Error("failed", args...)
+	var record map[string]any
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &record), buf.String())
+	return record
+}
+
+func TestAttr(t *testing.T) {
+	e := Err{Code: ec.NotFound, Msg: "config",
+		Cause: Err{Code: ecfs.NotADir, Cause: errors.New("raw")}.With(Path("/etc"))}.WithStack()
+	record := logJSON(t, nil, Attr(e))
+	outer := record["error"].(map[string]any)
+	require.Equal(t, float64(ec.NotFound), outer["code"])
+	require.Equal(t, "ec.NotFound", outer["name"])
+	require.Equal(t, "ec", outer["group"])
+	require.Equal(t, "config", outer["msg"])
+	source := outer["source"].(map[string]any)
+	require.Equal(t, "github.com/iotanbo/igu/pkg/errslog.TestAttr", source["function"])
+	require.NotContains(t, outer, "fields")
+
+	inner := outer["cause"].(map[string]any)
+	require.Equal(t, "ecfs.NotADir", inner["name"])
+	require.Equal(t, "ecfs", inner["group"])
+	require.Equal(t, map[string]any{"path": "/etc"}, inner["fields"])
+	require.Equal(t, "raw", inner["cause"])
+	require.NotContains(t, inner, "msg")
+	require.NotContains(t, inner, "source")
+}
+
+func TestMultiErrValue(t *testing.T) {
+	var me MultiErr
+	me.AddAt(1, Err{Code: ec.NotFound})
+	me.AddKey("/tmp/a", errors.New("other"))
+	record := logJSON(t, nil, "batch", Of(&me))
+	batch := record["batch"].(map[string]any)
+	require.Equal(t, float64(ec.Multiple), batch["code"])
+	items := batch["errors"].(map[string]any)
+	require.Equal(t, "ec.NotFound", items["1"].(map[string]any)["name"])
+	require.Equal(t, "other", items["/tmp/a"])
+
+	// Errors of a MultiErr converted with ToErr() are nested into the cause
+	record = logJSON(t, nil, Attr(me.ToErr()))
+	items = record["error"].(map[string]any)["cause"].(map[string]any)["errors"].(map[string]any)
+	require.Len(t, items, 2)
+}
+
+func TestReplaceAttr(t *testing.T) {
+	e := Err{Code: ec.TimedOut}
+	opts := &slog.HandlerOptions{ReplaceAttr: ReplaceAttr}
+	record := logJSON(t, opts, "err", e, "plain", errors.New("plain"), "n", 1)
+	require.Equal(t, "ec.TimedOut", record["err"].(map[string]any)["name"])
+	require.Equal(t, "plain", record["plain"])
+	require.Equal(t, float64(1), record["n"])
+}
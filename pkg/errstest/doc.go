@@ -0,0 +1,29 @@
+/*
+Package errstest provides test assertions for errs.Err in the style
+of testify's require package: a failed assertion reports the expected
+value and the whole actual error chain, then stops the test.
+Features:
+  - NoErr() and Some() check the presence of an error;
+  - Code() and InGroup() check the code of the top-level error;
+  - Contains() checks that an error code (see errs.Code()) or a wrapped
+    standard error is found anywhere in the chain;
+  - Msg(), ErrorContains() and Field() check messages and context fields.
+
+Usage example:
+
+	e := fu.CreateTextFile(path, "text", false)
+	errstest.NoErr(t, e)
+	e = fu.CreateTextFile(path, "text", false)
+	errstest.Code(t, e, ec.AlreadyExists, "second CreateTextFile(%s)", path)
+	errstest.Field(t, e, errs.KeyPath, path)
+
+Failure output example:
+
+	Error:    unexpected error code
+	Expected: ec.AlreadyExists (4)
+	Actual:   ec.PermissionDenied (2) [path=/root/a.txt]
+	            caused by: *fs.PathError "open /root/a.txt: permission denied"
+	            caused by: syscall.Errno "permission denied"
+	Messages: second CreateTextFile(/root/a.txt)
+*/
+package errstest
@@ -0,0 +1,241 @@
+package errstest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/iotanbo/igu/pkg/ecdef"
+	"github.com/iotanbo/igu/pkg/errs"
+)
+
+// TestingT is the subset of testing.TB used by the assertions.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+	FailNow()
+}
+
+type tHelper interface {
+	Helper()
+}
+
+// NoErr asserts that err is nil or an Err without error.
+func NoErr(t TestingT, err error, msgAndArgs ...interface{}) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	if isNone(err) {
+		return
+	}
+	fail(t, "unexpected error", "no error", err, msgAndArgs)
+}
+
+// Some asserts that err is an error, i.e. not nil and not NoError.
+func Some(t TestingT, err error, msgAndArgs ...interface{}) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	if !isNone(err) {
+		return
+	}
+	fail(t, "error expected", "any error", err, msgAndArgs)
+}
+
+// Code asserts that the top-level error of err is an Err with specified code.
+// Use Contains() to find the code anywhere in the chain.
+func Code(t TestingT, err error, code ecdef.ErrCode, msgAndArgs ...interface{}) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	if e, ok := errs.AsErr(err); ok && e.Code == code {
+		return
+	}
+	if err == nil && code == errs.NoError.Code {
+		return
+	}
+	fail(t, "unexpected error code", describeCode(code), err, msgAndArgs)
+}
+
+// InGroup asserts that the top-level error of err is an Err
+// with a code of the registered group with specified name,
+// e.g. ecdef.FS_GROUP.
+func InGroup(t TestingT, err error, group string, msgAndArgs ...interface{}) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	if e, ok := errs.AsErr(err); ok && e.InGroup(group) {
+		return
+	}
+	fail(t, "error code is not in the group", "a code of group "+group, err, msgAndArgs)
+}
+
+// Contains asserts that target is found anywhere in the chain of err,
+// see errors.Is(). The target can be an error code (e.g. errs.Code(ec.NotFound)),
+// an Err (matched by code) or any other error (e.g. fs.ErrNotExist).
+func Contains(t TestingT, err error, target error, msgAndArgs ...interface{}) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	if err != nil && errors.Is(err, target) {
+		return
+	}
+	fail(t, "error chain does not contain target", describeTarget(target), err, msgAndArgs)
+}
+
+// NotContains asserts that target is not found in the chain of err,
+// see Contains().
+func NotContains(t TestingT, err error, target error, msgAndArgs ...interface{}) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	if err == nil || !errors.Is(err, target) {
+		return
+	}
+	fail(t, "error chain contains target", "no "+describeTarget(target), err, msgAndArgs)
+}
+
+// Msg asserts that the top-level error of err is an Err with message msg.
+func Msg(t TestingT, err error, msg string, msgAndArgs ...interface{}) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	if e, ok := errs.AsErr(err); ok && e.Msg == msg {
+		return
+	}
+	fail(t, "unexpected error message", fmt.Sprintf("message %q", msg), err, msgAndArgs)
+}
+
+// ErrorContains asserts that the text of the whole chain
+// (as returned by err.Error()) contains substr.
+func ErrorContains(t TestingT, err error, substr string, msgAndArgs ...interface{}) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	if err != nil && strings.Contains(err.Error(), substr) {
+		return
+	}
+	fail(t, "error text does not contain substring", fmt.Sprintf("text containing %q", substr),
+		err, msgAndArgs)
+}
+
+// Field asserts that the context field with specified key found in the chain
+// of err (see errs.Err.Field()) is equal to value.
+func Field(t TestingT, err error, key string, value interface{}, msgAndArgs ...interface{}) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	if e, ok := errs.AsErr(err); ok {
+		if actual, ok := e.Field(key); ok && reflect.DeepEqual(actual, value) {
+			return
+		}
+	}
+	fail(t, "unexpected context field", fmt.Sprintf("%s=%#v", key, value), err, msgAndArgs)
+}
+
+func isNone(err error) bool {
+	if err == nil {
+		return true
+	}
+	e, ok := errs.AsErr(err)
+	return ok && e.None()
+}
+
+// fail reports a failed assertion and stops the test.
+func fail(t TestingT, problem, expected string, actual error, msgAndArgs []interface{}) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\nError:    %s\nExpected: %s\nActual:   ", problem, expected)
+	b.WriteString(strings.ReplaceAll(Describe(actual), "\n", "\n          "))
+	if msg := formatMsg(msgAndArgs); msg != "" {
+		fmt.Fprintf(&b, "\nMessages: %s", msg)
+	}
+	t.Errorf("%s", b.String())
+	t.FailNow()
+}
+
+func formatMsg(msgAndArgs []interface{}) string {
+	if len(msgAndArgs) == 0 {
+		return ""
+	}
+	if format, ok := msgAndArgs[0].(string); ok {
+		return fmt.Sprintf(format, msgAndArgs[1:]...)
+	}
+	return fmt.Sprint(msgAndArgs...)
+}
+
+func describeCode(code ecdef.ErrCode) string {
+	return fmt.Sprintf("%s (%d)", ecdef.CodeName(code), int64(code))
+}
+
+func describeTarget(target error) string {
+	if e, ok := target.(errs.Err); ok {
+		return describeCode(e.Code)
+	}
+	return fmt.Sprintf("%T %q", target, target)
+}
+
+// Describe pretty-prints the whole chain of err, a line per error:
+// errors of type Err with their code, name, message and context fields,
+// other errors with their type and text; errors collected
+// by errs.MultiErr are indented under it.
+func Describe(err error) string {
+	if isNone(err) {
+		if err == nil {
+			return "<nil>"
+		}
+		return "NoError"
+	}
+	var b strings.Builder
+	describe(&b, err, 0)
+	return b.String()
+}
+
+func describe(b *strings.Builder, err error, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for i := 0; err != nil; i++ {
+		if i > 0 {
+			fmt.Fprintf(b, "\n%s  caused by: ", indent)
+		}
+		if e, ok := errs.AsErr(err); ok {
+			b.WriteString(describeErr(e))
+			err = e.Cause
+			continue
+		}
+		if m, ok := err.(*errs.MultiErr); ok {
+			fmt.Fprintf(b, "*errs.MultiErr with %d errors:", m.Len())
+			for _, item := range m.Items {
+				key := item.Key
+				if item.Index >= 0 {
+					key = fmt.Sprint(item.Index)
+				}
+				fmt.Fprintf(b, "\n%s    [%s] ", indent, key)
+				describe(b, item.Err, depth+2)
+			}
+			return
+		}
+		fmt.Fprintf(b, "%T %q", err, err.Error())
+		err = errors.Unwrap(err)
+	}
+}
+
+func describeErr(e errs.Err) string {
+	r := describeCode(e.Code)
+	// The description of most codes adds nothing to their name
+	if text := ecdef.CodeToString(e.Code); text != ecdef.CodeName(e.Code) {
+		r += fmt.Sprintf(" %q", text)
+	}
+	if e.Msg != "" {
+		r += fmt.Sprintf(" msg=%q", e.Msg)
+	}
+	if fields := e.Fields(); len(fields) != 0 {
+		parts := make([]string, len(fields))
+		for i, f := range fields {
+			parts[i] = fmt.Sprintf("%s=%v", f.Key, f.Value)
+		}
+		r += " [" + strings.Join(parts, " ") + "]"
+	}
+	return r
+}
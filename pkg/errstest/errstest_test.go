@@ -0,0 +1,92 @@
+package errstest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecdef"
+	"github.com/iotanbo/igu/pkg/ecfs"
+	"github.com/iotanbo/igu/pkg/errs"
+	"github.com/stretchr/testify/require"
+)
+
+// mockT records failures instead of stopping the test.
+type mockT struct {
+	output string
+	failed bool
+}
+
+func (m *mockT) Errorf(format string, args ...interface{}) {
+	m.output += fmt.Sprintf(format, args...)
+}
+
+func (m *mockT) FailNow() { m.failed = true }
+
+func TestAssertions(t *testing.T) {
+	e := errs.Err{Code: ec.NotFound, Msg: "config",
+		Cause: errs.Err{Code: ecfs.NotADir, Cause: fs.ErrNotExist}.With(errs.Path("/etc"))}
+
+	// Passing assertions
+	NoErr(t, nil)
+	NoErr(t, errs.NoError)
+	Some(t, e)
+	Code(t, e, ec.NotFound)
+	Code(t, nil, ec.NoError)
+	InGroup(t, e, ecdef.BASIC_GROUP)
+	Contains(t, e, errs.Code(ecfs.NotADir))
+	Contains(t, e, errs.Err{Code: ec.NotFound})
+	Contains(t, e, fs.ErrNotExist)
+	NotContains(t, e, fs.ErrExist)
+	NotContains(t, nil, errs.Code(ec.NotFound))
+	Msg(t, e, "config")
+	ErrorContains(t, e, "not a directory")
+	Field(t, e, errs.KeyPath, "/etc")
+
+	// Failing assertions
+	failing := []func(m *mockT){
+		func(m *mockT) { NoErr(m, e) },
+		func(m *mockT) { Some(m, errs.NoError) },
+		func(m *mockT) { Code(m, e, ecfs.NotADir) },
+		func(m *mockT) { Code(m, errors.New("plain"), ec.Other) },
+		func(m *mockT) { InGroup(m, e, ecdef.FS_GROUP) },
+		func(m *mockT) { Contains(m, e, errs.Code(ec.PermissionDenied)) },
+		func(m *mockT) { Contains(m, nil, errs.Code(ec.NotFound)) },
+		func(m *mockT) { NotContains(m, e, fs.ErrNotExist) },
+		func(m *mockT) { Msg(m, e, "other") },
+		func(m *mockT) { ErrorContains(m, e, "denied") },
+		func(m *mockT) { Field(m, e, errs.KeyPath, "/usr") },
+		func(m *mockT) { Field(m, e, errs.KeyOp, "copy") },
+	}
+	for i, f := range failing {
+		m := &mockT{}
+		f(m)
+		require.True(t, m.failed, "assertion %d must fail", i)
+		require.Contains(t, m.output, "Expected: ")
+	}
+}
+
+func TestFailureOutput(t *testing.T) {
+	var me errs.MultiErr
+	me.AddKey("/a", errs.Err{Code: ec.PermissionDenied})
+	me.Add(errors.New("plain"))
+	e := errs.Err{Code: ec.NotFound, Msg: "config",
+		Cause: me.ToErr()}.With(errs.Path("/etc"), errs.Op("read"))
+
+	m := &mockT{}
+	Code(m, e, ec.AlreadyExists, "reading %s", "config")
+	require.Equal(t, `
+Error:    unexpected error code
+Expected: ec.AlreadyExists (4)
+Actual:   ec.NotFound (1) msg="config" [path=/etc op=read]
+            caused by: ec.Multiple (28) "ec.Multiple (multiple errors)"
+            caused by: *errs.MultiErr with 2 errors:
+              [/a] ec.PermissionDenied (2)
+              [1] *errors.errorString "plain"
+Messages: reading config`, m.output)
+
+	require.Equal(t, "<nil>", Describe(nil))
+	require.Equal(t, "NoError", Describe(errs.NoError))
+}
@@ -0,0 +1,602 @@
+package fu
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecfs"
+
+	//lint:ignore ST1001 - for concise error handling.
+	. "github.com/iotanbo/igu/pkg/errs"
+)
+
+// Permission of directories while their contents is being copied,
+// the original permission is restored afterwards.
+const tmpDirPermission os.FileMode = 0755
+
+// State of a single Copy call.
+type copier struct {
+	ctx context.Context
+	o   CopyOptions
+	// Root of the copied tree, used to resolve symlink targets.
+	srcRoot string
+	// Source directories being copied, used to detect
+	// symlink loops in SYMLINK_DEEP mode.
+	dirs []os.FileInfo
+	// Guards progress, completed and errs.
+	mu sync.Mutex
+	// Reported by o.Progress.
+	progress CopyProgress
+	// Source paths of completed items, recorded only if ctx can be canceled.
+	completed []string
+
+	// Parallel copy (o.Concurrency > 1) only:
+	// regular files to be copied by workers.
+	jobs chan copyJob
+	// Errors of workers and of the tree walk.
+	errs MultiErr
+	// Dirs to be completed after all files are copied,
+	// in order of completion of their walk (children before parents).
+	unfinished []copyJob
+}
+
+// Item to be copied by a worker or finished after the parallel copy.
+type copyJob struct {
+	src, dest string
+	info      os.FileInfo
+}
+
+// Copies src into dest; the checks of dest existence and
+// OVERWRITE_FULL are done by CopyContext.
+func copyTree(ctx context.Context, src, dest string, o CopyOptions) Err {
+	c := copier{ctx: ctx, o: o, srcRoot: src}
+	if o.PreScan {
+		if e := c.scan(src); e.Some() {
+			return e
+		}
+	}
+	info, err := os.Lstat(src)
+	if err != nil {
+		return FromError(err).With(Op("copy"), Path(src))
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), tmpDirPermission); err != nil {
+		return FromError(err).With(Op("copy"), Path(dest))
+	}
+	if o.Concurrency > 1 && info.IsDir() {
+		if e := c.copyParallel(src, dest, info); e.Some() {
+			return e
+		}
+	} else if e := c.copy(src, dest, info); e.Some() {
+		return e
+	}
+	c.report("", 0)
+	return NoError
+}
+
+// Walks src creating dirs while workers copy the files,
+// then applies permissions and times to the dirs.
+func (c *copier) copyParallel(src, dest string, info os.FileInfo) Err {
+	c.jobs = make(chan copyJob, c.o.Concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < c.o.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range c.jobs {
+				// After the first failure the queued jobs are dropped
+				if c.failed() {
+					continue
+				}
+				if e := c.copyNow(job.src, job.dest, job.info); e.Some() {
+					c.fail(job.src, e)
+				}
+			}
+		}()
+	}
+	e := c.copy(src, dest, info)
+	close(c.jobs)
+	wg.Wait()
+	if c.ctx.Err() != nil {
+		return c.canceled(c.interruptedPath(src, e))
+	}
+	// The walk is also stopped by failures of workers.
+	if e.Some() && !c.failed() {
+		path, _ := e.Field(KeyPath)
+		key, _ := path.(string)
+		c.fail(key, e)
+	}
+	if c.failed() {
+		return c.errs.ToErr().With(Op("copy"))
+	}
+	for _, job := range c.unfinished {
+		// Existing dirs (without info) keep their permissions and times.
+		if job.info != nil {
+			if e := c.finishDir(job.dest, job.info); e.Some() {
+				return e
+			}
+		}
+		c.complete(job.src)
+	}
+	return NoError
+}
+
+// Returns the source path of the first item stopped by the cancellation
+// in parallel copy (reported by a worker or by the walk returning walkErr),
+// or src if no item was being copied at that moment.
+func (c *copier) interruptedPath(src string, walkErr Err) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, err := range append(c.errs.Errors(), walkErr) {
+		if _, ok := ContextErrorCode(err); !ok {
+			continue
+		}
+		if e, ok := AsErr(err); ok {
+			if path, ok := e.Field(KeyPath); ok {
+				return path.(string)
+			}
+		}
+	}
+	return src
+}
+
+func (c *copier) fail(key string, e Err) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errs.AddKey(key, e)
+}
+
+func (c *copier) failed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.errs.Some()
+}
+
+// Counts the items and bytes to be copied from src. Unreadable items
+// are ignored, dirs symlinked in SYMLINK_DEEP mode are counted as one item.
+func (c *copier) scan(src string) Err {
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err := c.ctx.Err(); err != nil {
+			return err
+		}
+		if err != nil {
+			return nil
+		}
+		if path != src && c.o.Skip != nil {
+			if skip, err := c.o.Skip(path); err == nil && skip {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+		if info.Mode()&os.ModeSymlink != 0 && c.o.SymlinkMode == SYMLINK_DEEP {
+			if targetInfo, err := os.Stat(path); err == nil {
+				info = targetInfo
+			}
+		}
+		c.progress.TotalItems++
+		if info.Mode().IsRegular() {
+			c.progress.TotalBytes += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return c.canceled(src)
+	}
+	return NoError
+}
+
+// Reports the progress of item at path with itemBytes copied.
+func (c *copier) report(path string, itemBytes int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reportLocked(path, itemBytes, METHOD_UNKNOWN)
+}
+
+func (c *copier) reportLocked(path string, itemBytes int64, method CopyMethod) {
+	if c.o.Progress != nil {
+		p := c.progress
+		p.Path, p.ItemBytes, p.Method = path, itemBytes, method
+		c.o.Progress(p)
+	}
+}
+
+// Adds n bytes of file at path copied with method to the progress and reports it.
+func (c *copier) addBytes(path string, itemBytes, n int64, method CopyMethod) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.progress.Bytes += n
+	c.reportLocked(path, itemBytes, method)
+}
+
+func (c *copier) complete(src string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.progress.Items++
+	if c.ctx.Done() != nil {
+		c.completed = append(c.completed, src)
+	}
+}
+
+// Returns the error of canceled copy.
+func (c *copier) canceled(path string) Err {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return FromContext(c.ctx).With(Op("copy"), Path(path), KV(KEY_COMPLETED, c.completed))
+}
+
+// Copies item of any type and updates the progress.
+// In parallel copy, regular files are passed to the workers
+// and dirs are completed after all files are copied.
+func (c *copier) copy(src, dest string, info os.FileInfo) Err {
+	if c.jobs != nil {
+		if c.failed() {
+			// Stops the walk, the error is already recorded
+			return Err{Code: ec.Interrupted}
+		}
+		if info.Mode().IsRegular() {
+			c.jobs <- copyJob{src: src, dest: dest, info: info}
+			return NoError
+		}
+	}
+	return c.copyNow(src, dest, info)
+}
+
+func (c *copier) copyNow(src, dest string, info os.FileInfo) Err {
+	c.report(src, 0)
+	if c.ctx.Err() != nil {
+		return c.canceled(src)
+	}
+	if e := c.copyItem(src, dest, info); e.Some() {
+		return e
+	}
+	if c.jobs == nil || !info.IsDir() {
+		c.complete(src)
+	}
+	return NoError
+}
+
+func (c *copier) copyItem(src, dest string, info os.FileInfo) Err {
+	mode := info.Mode()
+	switch {
+	case mode&os.ModeSymlink != 0:
+		return c.copySymlink(src, dest)
+	case mode.IsDir():
+		return c.copyDir(src, dest, info)
+	case mode&os.ModeNamedPipe != 0:
+		return c.copyNamedPipe(dest, info)
+	case mode.IsRegular():
+		return c.copyFile(src, dest, info)
+	default:
+		return Err{Code: ec.Unsupported, Msg: mode.Type().String()}.
+			With(Op("copy"), Path(src))
+	}
+}
+
+// Prepares dest for copying an item into it according to the overwrite mode.
+// Returns false if the item must not be copied (dest is kept by MERGE).
+// Existing dirs are merged, existing items of other types are removed
+// if they are to be overwritten.
+func (c *copier) prepareDest(dest string, dir bool) (bool, Err) {
+	info, err := os.Lstat(dest)
+	if os.IsNotExist(err) {
+		return true, NoError
+	} else if err != nil {
+		return false, FromError(err).With(Op("copy"), Path(dest))
+	}
+	if c.o.OverwriteMode == NO_OVERWRITE {
+		return false, Err{Code: ec.AlreadyExists}.With(Op("copy"), Path(dest))
+	}
+	if dir && info.IsDir() {
+		return true, NoError
+	}
+	if c.o.OverwriteMode == MERGE {
+		return false, NoError
+	}
+	if dir || info.IsDir() {
+		t, _ := GetItemType(dest)
+		return false, Err{Code: ec.Type, Msg: t.String()}.With(Op("copy"), Path(dest))
+	}
+	if err := os.Remove(dest); err != nil {
+		return false, FromError(err).With(Op("copy"), Path(dest))
+	}
+	return true, NoError
+}
+
+func (c *copier) copyDir(src, dest string, info os.FileInfo) Err {
+	for _, d := range c.dirs {
+		if os.SameFile(d, info) {
+			return Err{Code: ecfs.SymlinkLoop}.With(Op("copy"), Path(src))
+		}
+	}
+	proceed, e := c.prepareDest(dest, true)
+	if e.Some() || !proceed {
+		return e
+	}
+	// Existing dirs keep their permissions and times.
+	created := false
+	if err := os.Mkdir(dest, tmpDirPermission); err == nil {
+		created = true
+	} else if !os.IsExist(err) {
+		return FromError(err).With(Op("copy"), Path(dest))
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return FromError(err).With(Op("copy"), Path(src))
+	}
+	c.dirs = append(c.dirs, info)
+	for _, entry := range entries {
+		path := filepath.Join(src, entry.Name())
+		if c.o.Skip != nil {
+			skip, err := c.o.Skip(path)
+			if err != nil {
+				return FromError(err).With(Op("copy"), Path(path))
+			}
+			if skip {
+				continue
+			}
+		}
+		entryInfo, err := entry.Info()
+		if err != nil {
+			return FromError(err).With(Op("copy"), Path(path))
+		}
+		if e := c.copy(path, filepath.Join(dest, entry.Name()), entryInfo); e.Some() {
+			return e
+		}
+	}
+	c.dirs = c.dirs[:len(c.dirs)-1]
+
+	if c.jobs != nil {
+		job := copyJob{src: src, dest: dest}
+		if created {
+			job.info = info
+		}
+		c.unfinished = append(c.unfinished, job)
+		return NoError
+	}
+	if !created {
+		return NoError
+	}
+	return c.finishDir(dest, info)
+}
+
+// Applies permissions and times of the source dir to created dest.
+func (c *copier) finishDir(dest string, info os.FileInfo) Err {
+	if err := os.Chmod(dest, info.Mode()|c.o.AddPermission); err != nil {
+		return FromError(err).With(Op("copy"), Path(dest))
+	}
+	return c.preserveTimes(dest, info)
+}
+
+func (c *copier) copyFile(src, dest string, info os.FileInfo) Err {
+	proceed, e := c.prepareDest(dest, false)
+	if e.Some() || !proceed {
+		return e
+	}
+	s, err := os.Open(src)
+	if err != nil {
+		return FromError(err).With(Op("copy"), Path(src))
+	}
+	defer s.Close()
+	f, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return FromError(err).With(Op("copy"), Path(dest))
+	}
+	if e := c.writeFile(f, s, src, info); e.Some() {
+		// Partially written file is useless
+		f.Close()
+		os.Remove(dest)
+		if c.ctx.Err() != nil {
+			return c.canceled(src)
+		}
+		return e.With(Op("copy"), Path(dest))
+	}
+	if err := f.Close(); err != nil {
+		return FromError(err).With(Op("copy"), Path(dest))
+	}
+	return c.preserveTimes(dest, info)
+}
+
+// Copies contents of s into f and sets its permissions.
+func (c *copier) writeFile(f, s *os.File, src string, info os.FileInfo) Err {
+	if err := f.Chmod(info.Mode() | c.o.AddPermission); err != nil {
+		return FromError(err)
+	}
+	if e := c.writeContents(f, s, src, info.Size()); e.Some() {
+		return e
+	}
+	if c.o.Sync {
+		if err := f.Sync(); err != nil {
+			return FromError(err)
+		}
+	}
+	return NoError
+}
+
+// Returned by the platform-specific fast copy functions
+// if the method is not available for the files.
+var errFastCopyUnsupported = errors.New("fast copy method not supported")
+
+// Size of chunks copied by the kernel between checks
+// of cancellation and progress reports.
+const kernelCopyChunk = 8 << 20
+
+// Kernel copy methods in order of preference.
+var kernelCopyMethods = []struct {
+	method CopyMethod
+	copy   func(dst, src *os.File, n int) (int, error)
+}{
+	{METHOD_COPY_FILE_RANGE, copyFileRange},
+	{METHOD_SENDFILE, sendFile},
+}
+
+// Copies contents of s into f with the fastest method allowed by the strategy.
+func (c *copier) writeContents(f, s *os.File, src string, size int64) Err {
+	strategy := c.o.Strategy
+	if strategy == COPY_AUTO || strategy == COPY_REFLINK || strategy == COPY_REFLINK_OR_COPY {
+		err := reflinkFile(f, s)
+		if err == nil {
+			c.addBytes(src, size, size, METHOD_REFLINK)
+			return NoError
+		} else if err != errFastCopyUnsupported {
+			return FromError(err)
+		}
+		if strategy == COPY_REFLINK {
+			return Err{Code: ec.Unsupported, Msg: "reflink"}
+		}
+	}
+	// Sizes of empty special files (e.g. in /proc) are not reliable,
+	// they are copied in user space.
+	if strategy == COPY_KERNEL || (strategy == COPY_AUTO && size > 0) {
+		for _, m := range kernelCopyMethods {
+			if done, e := c.kernelCopy(f, s, src, size, m.method, m.copy); e.Some() || done {
+				return e
+			}
+		}
+		if strategy == COPY_KERNEL {
+			return Err{Code: ec.Unsupported, Msg: "kernel copy"}
+		}
+	}
+	return c.userspaceCopy(f, s, src)
+}
+
+// Copies size bytes of s into f by chunks with the copy function of method.
+// Returns false if the method is not available, nothing is copied then.
+func (c *copier) kernelCopy(f, s *os.File, src string, size int64, method CopyMethod,
+	copy func(dst, src *os.File, n int) (int, error)) (bool, Err) {
+	var written int64
+	for written < size {
+		if err := c.ctx.Err(); err != nil {
+			return false, FromError(err)
+		}
+		chunk := size - written
+		if chunk > kernelCopyChunk {
+			chunk = kernelCopyChunk
+		}
+		n, err := copy(f, s, int(chunk))
+		if err == errFastCopyUnsupported && written == 0 {
+			return false, NoError
+		} else if err != nil {
+			return false, FromError(err)
+		}
+		if n == 0 {
+			if written == 0 {
+				return false, NoError
+			}
+			break // src was truncated while copying
+		}
+		written += int64(n)
+		c.addBytes(src, written, int64(n), method)
+	}
+	return true, NoError
+}
+
+func (c *copier) userspaceCopy(f, s *os.File, src string) Err {
+	// Hide ReadFrom() and WriteTo() of os.File,
+	// they use kernel copy when possible.
+	var w io.Writer = struct{ io.Writer }{f}
+	if c.o.Progress != nil || c.ctx.Done() != nil {
+		w = &progressWriter{w: f, c: c, path: src}
+	}
+	var buf []byte
+	if c.o.CopyBufferSize != 0 {
+		buf = make([]byte, c.o.CopyBufferSize)
+	}
+	if _, err := io.CopyBuffer(w, struct{ io.Reader }{s}, buf); err != nil {
+		return FromError(err)
+	}
+	return NoError
+}
+
+func (c *copier) copySymlink(src, dest string) Err {
+	if c.o.SymlinkMode == SYMLINK_DEEP {
+		info, err := os.Stat(src)
+		if err != nil {
+			return FromError(err).With(Op("copy"), Path(src))
+		}
+		return c.copyItem(src, dest, info)
+	}
+	target, err := os.Readlink(src)
+	if err != nil {
+		return FromError(err).With(Op("copy"), Path(src))
+	}
+	if c.o.SymlinkMode == SYMLINK_SHALLOW {
+		target = c.shallowTarget(src, target)
+	}
+	proceed, e := c.prepareDest(dest, false)
+	if e.Some() || !proceed {
+		return e
+	}
+	if err := os.Symlink(target, dest); err != nil {
+		return FromError(err).With(Op("copy"), Path(dest))
+	}
+	return NoError
+}
+
+// Returns the target of the copy of symlink at path:
+// relative targets inside the copied tree are kept,
+// the ones outside of it are made absolute.
+func (c *copier) shallowTarget(path, target string) string {
+	if filepath.IsAbs(target) {
+		return target
+	}
+	resolved := filepath.Join(filepath.Dir(path), target)
+	rel, err := filepath.Rel(c.srcRoot, resolved)
+	if err == nil && path != c.srcRoot && rel != ".." &&
+		!strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return target
+	}
+	if abs, err := filepath.Abs(resolved); err == nil {
+		return abs
+	}
+	return resolved
+}
+
+func (c *copier) copyNamedPipe(dest string, info os.FileInfo) Err {
+	proceed, e := c.prepareDest(dest, false)
+	if e.Some() || !proceed {
+		return e
+	}
+	if e := createNamedPipe(dest, info.Mode()|c.o.AddPermission); e.Some() {
+		return e.With(Op("copy"), Path(dest))
+	}
+	return c.preserveTimes(dest, info)
+}
+
+func (c *copier) preserveTimes(dest string, info os.FileInfo) Err {
+	if !c.o.PreserveTimes {
+		return NoError
+	}
+	if err := os.Chtimes(dest, accessTime(info), info.ModTime()); err != nil {
+		return FromError(err).With(Op("copy"), Path(dest))
+	}
+	return NoError
+}
+
+// Writer that stops when the copy is canceled and reports
+// the progress after each write.
+type progressWriter struct {
+	w io.Writer
+	c *copier
+	// Source path of the copied file.
+	path string
+	// Bytes written so far.
+	n int64
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	if err := p.c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := p.w.Write(b)
+	p.n += int64(n)
+	p.c.addBytes(p.path, p.n, int64(n), METHOD_USERSPACE)
+	return n, err
+}
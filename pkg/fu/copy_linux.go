@@ -0,0 +1,51 @@
+package fu
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// Clones contents of src into dst with FICLONE ioctl.
+// Returns errFastCopyUnsupported if the file system can't do it.
+func reflinkFile(dst, src *os.File) error {
+	err := unix.IoctlFileClone(int(dst.Fd()), int(src.Fd()))
+	if isUnsupportedErrno(err, unix.EOPNOTSUPP, unix.EXDEV, unix.EINVAL,
+		unix.ENOTTY, unix.ENOSYS, unix.EPERM) {
+		return errFastCopyUnsupported
+	}
+	return err
+}
+
+// Copies up to n bytes from the current offset of src into dst
+// with copy_file_range. Returns errFastCopyUnsupported
+// if the kernel or file system can't do it.
+func copyFileRange(dst, src *os.File, n int) (int, error) {
+	written, err := unix.CopyFileRange(int(src.Fd()), nil, int(dst.Fd()), nil, n, 0)
+	if isUnsupportedErrno(err, unix.ENOSYS, unix.EXDEV, unix.EINVAL,
+		unix.EOPNOTSUPP, unix.EPERM) {
+		return 0, errFastCopyUnsupported
+	}
+	return written, err
+}
+
+// Copies up to n bytes from the current offset of src into dst
+// with sendfile. Returns errFastCopyUnsupported
+// if the kernel or file system can't do it.
+func sendFile(dst, src *os.File, n int) (int, error) {
+	written, err := unix.Sendfile(int(dst.Fd()), int(src.Fd()), nil, n)
+	if isUnsupportedErrno(err, unix.ENOSYS, unix.EINVAL) {
+		return 0, errFastCopyUnsupported
+	}
+	return written, err
+}
+
+func isUnsupportedErrno(err error, errnos ...unix.Errno) bool {
+	for _, errno := range errnos {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+// +build !linux
+
+package fu
+
+import "os"
+
+// Reflinks are not supported on this platform yet.
+func reflinkFile(dst, src *os.File) error {
+	return errFastCopyUnsupported
+}
+
+// copy_file_range is linux-only.
+func copyFileRange(dst, src *os.File, n int) (int, error) {
+	return 0, errFastCopyUnsupported
+}
+
+// Copying with sendfile is not supported on this platform yet.
+func sendFile(dst, src *os.File, n int) (int, error) {
+	return 0, errFastCopyUnsupported
+}
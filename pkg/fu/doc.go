@@ -3,6 +3,17 @@ Package fu (File Utilities) implements utility functions
 for working with file system items.
 Features:
 	* unified Copy function for copying file system items of any type;
+	* files of directory trees are copied in parallel
+	  with CopyOptions.Concurrency, errors are collected into errs.MultiErr;
+	* file contents is copied with reflinks, copy_file_range or sendfile
+	  when available, see CopyOptions.Strategy and CopyProgress.Method;
+	* CopyContext reports progress of long copies and stops
+	  when its context is canceled;
+	* Move function that renames items atomically when possible
+	  and falls back to copying and removing the source
+	  when moving between file systems;
+	* returned errors carry the offending path in the errs.KeyPath
+	  context field, e.g. `path, _ := e.Field(errs.KeyPath)`;
 
 References:
 
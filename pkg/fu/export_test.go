@@ -0,0 +1,9 @@
+package fu
+
+// SetRename replaces the function used by Move to rename items
+// and returns a function that restores the original one.
+func SetRename(f func(oldpath, newpath string) error) (restore func()) {
+	orig := rename
+	rename = f
+	return func() { rename = orig }
+}
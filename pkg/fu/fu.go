@@ -2,13 +2,12 @@ package fu
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 
-	otiai10 "github.com/iotanbo/copy"
-
 	"github.com/iotanbo/igu/pkg/ec"
 
 	//lint:ignore ST1001 - for concise error handling.
@@ -37,11 +36,13 @@ type FsItemType int32
 type SymlinkCopyMode int32
 
 const (
-	// SYMLINK_SHALLOW creates a new symlink pointing to dest.
+	// SYMLINK_SHALLOW creates a new symlink pointing to the same item
+	// as the source one: relative targets inside the copied tree are kept,
+	// relative targets outside of it are made absolute.
 	SYMLINK_SHALLOW SymlinkCopyMode = iota
 	// SYMLINK_DEEP creates hard-copy of contents.
 	SYMLINK_DEEP
-	// SYMLINK_UNMODIFIED copies symlink as is, not modifying it.
+	// SYMLINK_UNMODIFIED copies symlink as is, not modifying its target.
 	SYMLINK_UNMODIFIED
 )
 
@@ -61,12 +62,69 @@ const (
 	// it will be preserved but any common file inside it will be overwritten.
 	// Items unique to destination will be kept intact.
 	OVERWRITE_INTERSECTION
-	// OVERWRITE_FULL: delete destination if exists
-	// and then completely rewrite it.
+	// OVERWRITE_FULL: completely replace destination if exists.
+	// The new destination is created in a temporary sibling directory
+	// and swapped in place of the old one, which is deleted afterwards,
+	// so the old destination is kept intact if the operation fails.
 	// All items unique to destination will be lost.
 	OVERWRITE_FULL
 )
 
+// CopyStrategy defines how contents of regular files is copied.
+type CopyStrategy int32
+
+const (
+	// COPY_AUTO tries the fastest method available for each file:
+	// reflink, then kernel copy, then userspace copy.
+	COPY_AUTO CopyStrategy = iota
+	// COPY_REFLINK only clones files (e.g. on btrfs, XFS), returns
+	// ec.Unsupported if the file system or platform doesn't support it.
+	COPY_REFLINK
+	// COPY_REFLINK_OR_COPY clones files if possible,
+	// otherwise copies them in user space.
+	COPY_REFLINK_OR_COPY
+	// COPY_KERNEL copies inside the kernel with copy_file_range or sendfile,
+	// returns ec.Unsupported if neither is available.
+	COPY_KERNEL
+	// COPY_USERSPACE reads and writes contents through a buffer,
+	// see CopyOptions.CopyBufferSize.
+	COPY_USERSPACE
+)
+
+// CopyMethod is the method a file was actually copied with,
+// see CopyProgress.Method.
+type CopyMethod int32
+
+const (
+	// No file contents copied (yet).
+	METHOD_UNKNOWN CopyMethod = iota
+	// File was cloned with reflink (FICLONE).
+	METHOD_REFLINK
+	// File was copied with copy_file_range.
+	METHOD_COPY_FILE_RANGE
+	// File was copied with sendfile.
+	METHOD_SENDFILE
+	// File was read and written through a buffer in user space.
+	METHOD_USERSPACE
+)
+
+func (m CopyMethod) String() string {
+	switch m {
+	case METHOD_UNKNOWN:
+		return "METHOD_UNKNOWN"
+	case METHOD_REFLINK:
+		return "METHOD_REFLINK"
+	case METHOD_COPY_FILE_RANGE:
+		return "METHOD_COPY_FILE_RANGE"
+	case METHOD_SENDFILE:
+		return "METHOD_SENDFILE"
+	case METHOD_USERSPACE:
+		return "METHOD_USERSPACE"
+	default:
+		return fmt.Sprintf("CopyMethod(%d)", m)
+	}
+}
+
 // CopyOptions specifies options to be applied when copying or moving file or directory.
 type CopyOptions struct {
 	// SymlinkMode defines how to copy symlinks, must be one of
@@ -96,12 +154,58 @@ type CopyOptions struct {
 	// On linux we can preserve only up to 1 millisecond accuracy.
 	PreserveTimes bool
 
-	// The byte size of the buffer to use for copying files.
+	// The byte size of the buffer to use for copying files in user space.
 	// If zero, the internal default buffer of 32KB is used.
 	// See https://golang.org/pkg/io/#CopyBuffer for more information.
 	CopyBufferSize uint
+
+	// Strategy defines how file contents is copied: [COPY_AUTO (default),
+	// COPY_REFLINK, COPY_REFLINK_OR_COPY, COPY_KERNEL, COPY_USERSPACE].
+	Strategy CopyStrategy
+
+	// Progress is called when copying of an item starts, after each
+	// buffer of file contents is written and once when copying
+	// is complete (with empty Path). It is called synchronously,
+	// a slow callback slows down copying; it is never called concurrently.
+	Progress func(p CopyProgress)
+
+	// Walk the source tree before copying to fill in the totals
+	// of CopyProgress.
+	PreScan bool
+
+	// Number of files copied in parallel when copying a directory,
+	// 0 or 1 copies files one after another. Dirs are created before
+	// their contents and get their permissions and times after all files
+	// are copied. Errors of all parallel copies are collected into
+	// errs.MultiErr, which is the Cause of the returned error.
+	// Useful for many small files on SSDs and network file systems.
+	Concurrency int
 }
 
+// CopyProgress describes the state of a running copy,
+// see CopyOptions.Progress.
+type CopyProgress struct {
+	// Source path of the item being copied.
+	Path string
+	// Bytes of the current file copied so far.
+	ItemBytes int64
+	// Bytes of all files copied so far.
+	Bytes int64
+	// Number of completely processed items.
+	Items int
+	// Total bytes of files to be copied, zero if PreScan is false.
+	TotalBytes int64
+	// Total number of items to be processed, zero if PreScan is false.
+	TotalItems int
+	// Method the current file is copied with,
+	// set in the reports made after its contents is written.
+	Method CopyMethod
+}
+
+// Key of the context field of the error returned by CopyContext()
+// when it is canceled, holds the source paths of completed items.
+const KEY_COMPLETED = "completed"
+
 const (
 	// File system item type is unknown.
 	TYPE_UNKNOWN FsItemType = iota
@@ -180,7 +284,7 @@ func PathExistsTypeMatches(path string, tExpected FsItemType,
 			return true, NoError
 		}
 	}
-	return false, Err{Code: ec.Type, Msg: t.String()}
+	return false, Err{Code: ec.Type, Msg: t.String()}.With(Path(path))
 }
 
 // FileExists returns (true, NoError) if specified path exists and is of type
@@ -239,80 +343,6 @@ func NamedPipeExists(path string) (bool, Err) {
 	return PathExistsTypeMatches(path, TYPE_NAMED_PIPE)
 }
 
-// Translates CopyOptions into underlying implementation copy options
-// which are somewhat cumbersome to be used directly.
-func translateCopyOptions(o CopyOptions) otiai10.Options {
-	var r = otiai10.Options{}
-
-	// OnSymlink
-	switch o.SymlinkMode {
-	case SYMLINK_SHALLOW:
-		r.OnSymlink = func(src string) otiai10.SymlinkAction {
-			return otiai10.Shallow
-		}
-	case SYMLINK_DEEP:
-		r.OnSymlink = func(src string) otiai10.SymlinkAction {
-			return otiai10.Deep
-		}
-	case SYMLINK_UNMODIFIED:
-		r.OnSymlink = func(src string) otiai10.SymlinkAction {
-			return otiai10.Skip
-		}
-	}
-	// OnDirExists
-	switch o.OverwriteMode {
-	case NO_OVERWRITE:
-		r.OnDestExists = func(src, dest string) otiai10.DestExistsAction {
-			return otiai10.NoOverwrite
-		}
-	case MERGE:
-		r.OnDestExists = func(src, dest string) otiai10.DestExistsAction {
-			return otiai10.Merge
-		}
-
-	case OVERWRITE_INTERSECTION:
-		r.OnDestExists = func(src, dest string) otiai10.DestExistsAction {
-			return otiai10.OverwriteIntersection
-		}
-	}
-
-	// The rest of fields can be copied directly
-	r.Skip = o.Skip
-	if r.Skip == nil {
-		r.Skip = func(string) (bool, error) {
-			return false, nil // Don't skip
-		}
-	}
-	// Special Skip function to skip overwriting destination if it exists.
-	// if o.OverwriteMode == MERGE {
-	// 	r.Skip = func(path string) (bool, error) {
-	// 		// fmt.Printf("* DEBUG processing path '%s'\n", path)
-	// 		exists, itemType, e := PathExists(path)
-	// 		if e.Some() {
-	// 			return true, fmt.Errorf("can't check if path exists: '%s'", path)
-	// 		}
-
-	// 		if !exists {
-	// 			// Don't skip if destination does not exist
-	// 			fmt.Printf("* DEBUG path '%s' NOT exists, NOT skipping\n", path)
-	// 			return false, nil
-	// 		}
-	// 		if itemType == TYPE_DIR {
-	// 			fmt.Printf("* DEBUG path '%s' exists but is DIR, NOT skipping\n", path)
-	// 			return false, nil
-	// 		}
-	// 		fmt.Printf("* DEBUG path '%s' exists, SKIPPING\n", path)
-	// 		return true, nil // Skip
-	// 	}
-	// }
-
-	r.AddPermission = o.AddPermission
-	r.Sync = o.Sync
-	r.PreserveTimes = o.PreserveTimes
-	r.CopyBufferSize = o.CopyBufferSize
-	return r
-}
-
 // Copy copies any kind of file system items (file, dir, symlink etc.)
 // into dest using specified options.
 // The default options are: symlink shallow copy, no overwrite dest, no skip,
@@ -320,7 +350,9 @@ func translateCopyOptions(o CopyOptions) otiai10.Options {
 // use default 32KB buffer. Returns NoError if success. Otherwise:
 //	ec.NotFound // src not exists
 //	ec.AlreadyExists // dest exists and DestOverwriteMode is NO_OVERWRITE
-//	ec.Type // dest exists and has type different from src
+//	ec.Type // dest or an item inside it has type different from src
+//	ec.Unsupported // src contains items that can't be copied, e.g. sockets
+//	ecfs.SymlinkLoop // symlinks form a loop in SYMLINK_DEEP mode
 //	ec.PermissionDenied
 //	ec.TimedOut
 //	...or other less common errors.
@@ -330,7 +362,7 @@ func translateCopyOptions(o CopyOptions) otiai10.Options {
 //	e := Copy("/src", "/dest")
 //	// Allow dest overwriting
 //	e = Copy("/src", "/dest", CopyOptions{
-//			OverwriteMode: OVERWRITE})
+//			OverwriteMode: OVERWRITE_FULL})
 //	// Skip items that contain "temp" in their path
 //	e = Copy("/src", "/dest", CopyOptions{
 //			Skip: func(src string) (bool, error) {
@@ -341,6 +373,32 @@ func translateCopyOptions(o CopyOptions) otiai10.Options {
 //			},
 //		})
 func Copy(src, dest string, options ...CopyOptions) Err {
+	return CopyContext(context.Background(), src, dest, options...)
+}
+
+// CopyContext is same as Copy() but stops when ctx is done.
+// The file being copied at that moment is removed from dest,
+// items copied before are kept (with OVERWRITE_FULL, existing dest
+// is kept intact instead). Additionally to the errors of Copy()
+// returns ec.Canceled or ec.DeadlineExceeded with context fields
+// KEY_COMPLETED (source paths of completely processed items, []string)
+// and errs.KeyPath (the item being processed when copying stopped,
+// or src if it stopped between items).
+//
+// Usage example:
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	e := CopyContext(ctx, "/src", "/dest", CopyOptions{
+//		PreScan: true,
+//		Progress: func(p CopyProgress) {
+//			fmt.Printf("\r%d of %d bytes", p.Bytes, p.TotalBytes)
+//		},
+//	})
+//	if e.Eq(ec.Canceled) {
+//		completed, _ := e.Field(KEY_COMPLETED)
+//		...
+//	}
+func CopyContext(ctx context.Context, src, dest string, options ...CopyOptions) Err {
 	var o CopyOptions
 	if len(options) > 0 {
 		o = options[0]
@@ -353,7 +411,7 @@ func Copy(src, dest string, options ...CopyOptions) Err {
 		return e
 	}
 	if !srcExists {
-		return Err{Code: ec.NotFound}
+		return Err{Code: ec.NotFound}.With(Op("copy"), Path(src))
 	}
 	destExists, destType, e := PathExists(dest)
 	if e.Some() {
@@ -362,25 +420,56 @@ func Copy(src, dest string, options ...CopyOptions) Err {
 	if destExists {
 		if srcType == destType {
 			if o.OverwriteMode == NO_OVERWRITE {
-				return Err{Code: ec.AlreadyExists, Msg: dest}
+				return Err{Code: ec.AlreadyExists}.With(Op("copy"), Path(dest))
 			} else if o.OverwriteMode == OVERWRITE_FULL {
-				// OVERWRITE_FULL is treated only here;
-				// otiai10.Copy function does not have a notion
-				// of this mode.
-				if err := os.RemoveAll(dest); err != nil {
-					return FromError(err)
-				}
+				// OVERWRITE_FULL is treated only here,
+				// the copied tree is written into a temporary dest
+				// that replaces the existing one.
 				o.OverwriteMode = NO_OVERWRITE
+				return replaceDest(dest, "copy", func(tmp string) Err {
+					return copyTree(ctx, src, tmp, o)
+				})
 			}
 		} else {
 			// Dest already exists but its type doesn't match src
-			return Err{Code: ec.Type}
+			return Err{Code: ec.Type, Msg: destType.String()}.With(Op("copy"), Path(dest))
 		}
 	}
-	trOpts := translateCopyOptions(o)
-	err := otiai10.Copy(src, dest, trOpts)
+	return copyTree(ctx, src, dest, o)
+}
+
+// Replaces existing dest with a new item created by create() at path tmp,
+// a temporary sibling of dest: dest is moved aside, tmp is renamed
+// into dest, then the old dest is removed. If create() fails,
+// dest is kept intact. If the new item can't be swapped in,
+// the old dest is restored and the new item is kept at tmp,
+// its path is reported in the error message.
+func replaceDest(dest, op string, create func(tmp string) Err) Err {
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".igu-")
 	if err != nil {
-		return FromError(err)
+		return FromError(err).With(Op(op), Path(dest))
+	}
+	tmp := filepath.Join(tmpDir, "new")
+	old := filepath.Join(tmpDir, "old")
+	if e := create(tmp); e.Some() {
+		os.RemoveAll(tmpDir)
+		return e
+	}
+	if err := os.Rename(dest, old); err != nil {
+		e := FromError(err)
+		e.Msg = "new item kept in " + tmp
+		return e.With(Op(op), Path(dest))
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		os.Rename(old, dest)
+		e := FromError(err)
+		e.Msg = "new item kept in " + tmp
+		return e.With(Op(op), Path(dest))
+	}
+	if err := os.RemoveAll(tmpDir); err != nil {
+		e := FromError(err)
+		e.Msg = "replaced but failed to remove old item"
+		return e.With(Op(op), Path(old))
 	}
 	return NoError
 }
@@ -403,26 +492,26 @@ func CreateBinFile(path string, contents []byte, overwrite bool) Err {
 	}
 	if exists {
 		if !overwrite {
-			return Err{Code: ec.AlreadyExists, Msg: path}
+			return Err{Code: ec.AlreadyExists}.With(Path(path))
 		}
 		// Delete old file
 		err := os.Remove(path)
 		if err != nil {
-			return FromError(err)
+			return FromError(err).With(Path(path))
 		}
 	}
 	f, err := os.Create(path)
 	if err != nil {
-		return FromError(err)
+		return FromError(err).With(Path(path))
 	}
 	defer f.Close()
 	_, err = f.Write(contents)
 	if err != nil {
-		return FromError(err)
+		return FromError(err).With(Path(path))
 	}
 	err = f.Sync()
 	if err != nil {
-		return FromError(err)
+		return FromError(err).With(Path(path))
 	}
 	return NoError
 }
@@ -446,34 +535,34 @@ func CreateTextFile(path, contents string, overwrite bool) Err {
 	}
 	if exists {
 		if !overwrite {
-			return Err{Code: ec.AlreadyExists, Msg: path}
+			return Err{Code: ec.AlreadyExists}.With(Path(path))
 		}
 		// Delete old file
 		err := os.Remove(path)
 		if err != nil {
-			return FromError(err)
+			return FromError(err).With(Path(path))
 		}
 	} else {
 		// Create directory if not exists
 		d := filepath.Dir(path)
 		if err := os.MkdirAll(d, 0755); err != nil {
 			e := FromError(err)
-			e.Msg = "can't create directory: " + d
-			return e
+			e.Msg = "can't create directory"
+			return e.With(Op("mkdir"), Path(d))
 		}
 	}
 	f, err := os.Create(path)
 	if err != nil {
-		return FromError(err)
+		return FromError(err).With(Path(path))
 	}
 	defer f.Close()
 	_, err = f.WriteString(contents)
 	if err != nil {
-		return FromError(err)
+		return FromError(err).With(Path(path))
 	}
 	err = f.Sync()
 	if err != nil {
-		return FromError(err)
+		return FromError(err).With(Path(path))
 	}
 	return NoError
 }
@@ -494,14 +583,14 @@ func ReadBinFile(path string) ([]byte, Err) {
 		return result, e
 	}
 	if !exists {
-		return result, Err{Code: ec.NotFound, Msg: path}
+		return result, Err{Code: ec.NotFound}.With(Path(path))
 	}
 	if t == TYPE_DIR {
-		return result, Err{Code: ec.Type, Msg: "TYPE_DIR"}
+		return result, Err{Code: ec.Type, Msg: "TYPE_DIR"}.With(Path(path))
 	}
 	result, err := ioutil.ReadFile(path)
 	if err != nil {
-		return result, FromError(err)
+		return result, FromError(err).With(Path(path))
 	}
 	return result, NoError
 }
@@ -545,15 +634,15 @@ func ReadLines(path string) ([]string, Err) {
 		return result, e
 	}
 	if !exists {
-		return result, Err{Code: ec.NotFound, Msg: path}
+		return result, Err{Code: ec.NotFound}.With(Path(path))
 	}
 	if ty == TYPE_DIR {
-		return result, Err{Code: ec.Type, Msg: "TYPE_DIR"}
+		return result, Err{Code: ec.Type, Msg: "TYPE_DIR"}.With(Path(path))
 	}
 
 	file, err := os.Open(path)
 	if err != nil {
-		return result, FromError(err)
+		return result, FromError(err).With(Path(path))
 	}
 	defer file.Close()
 
@@ -565,7 +654,7 @@ func ReadLines(path string) ([]string, Err) {
 		result = append(result, scanner.Text())
 	}
 	if err := scanner.Err(); err != nil {
-		return result, FromError(err)
+		return result, FromError(err).With(Path(path))
 	}
 	return result, NoError
 }
@@ -2,3 +2,17 @@ package fu
 
 // lint:ignore ST1001 - for concise error handling.
 //. "github.com/iotanbo/igu/pkg/errs"
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// Returns the last access time of the item with specified info.
+func accessTime(info os.FileInfo) time.Time {
+	if s, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(s.Atimespec.Unix())
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !windows
+// +build !linux,!darwin,!windows
+
+package fu
+
+import (
+	"os"
+	"time"
+)
+
+// Returns the last access time of the item with specified info;
+// not available on this platform, the modification time is used instead.
+func accessTime(info os.FileInfo) time.Time {
+	return info.ModTime()
+}
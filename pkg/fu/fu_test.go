@@ -2,14 +2,22 @@ package fu_test
 
 import (
 	//"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	//"github.com/iotanbo/igu/pkg/fstestutils"
 	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecfs"
+	"github.com/iotanbo/igu/pkg/errs"
+	"github.com/iotanbo/igu/pkg/errstest"
 	"github.com/iotanbo/igu/pkg/fstestutils"
 	"github.com/iotanbo/igu/pkg/fu"
 	"github.com/stretchr/testify/require"
@@ -144,37 +152,32 @@ func TestGetItemType(t *testing.T) {
 
 	// When passing empty string, should return (TYPE_UNKNOWN, ec.NotFound).
 	r, e := fu.GetItemType("")
-	expect(t, e.Eq(ec.NotFound),
-		`GetItemType(''): expected ec.NotFound, got '%v'`, e)
+	errstest.Code(t, e, ec.NotFound, `GetItemType('')`)
 	expect(t, r == fu.TYPE_UNKNOWN,
 		`GetItemType(''): expected TYPE_UNKNOWN, got '%v'`, r)
 
 	// When passing existing file, should return (TYPE_FILE, NoError).
 	r, e = fu.GetItemType(existingFile)
-	expect(t, e.None(),
-		`GetItemType(existingFile): returned error '%v'`, e)
+	errstest.NoErr(t, e, `GetItemType(existingFile)`)
 	expect(t, r == fu.TYPE_FILE,
 		`GetItemType(existingFile): expected TYPE_FILE, got '%v'`, r)
 
 	// When passing non-existing file, should return (TYPE_UNKNOWN, ec.NotFound).
 	r, e = fu.GetItemType(nonExistingPath)
-	expect(t, e.Eq(ec.NotFound),
-		`GetItemType(nonExistingPath): expected ec.NotFound, got '%v'`, e)
+	errstest.Code(t, e, ec.NotFound, `GetItemType(nonExistingPath)`)
 	expect(t, r == fu.TYPE_UNKNOWN,
 		`GetItemType(nonExistingPath): expected TYPE_UNKNOWN, got '%v'`, r)
 
 	// When passing directory, should return (TYPE_DIR, NoError).
 	r, e = fu.GetItemType(testDirTreeRoot)
-	expect(t, e.None(),
-		`GetItemType(existingDir): expected ec.NoError, got '%v'`, e)
+	errstest.NoErr(t, e, `GetItemType(existingDir)`)
 	expect(t, r == fu.TYPE_DIR,
 		`GetItemType(existingDir): expected TYPE_DIR, got '%v'`, r)
 
 	// UNIX-ONLY
 	// When passing symlink, should return (TYPE_SYMLINK, NoError).
 	r, e = fu.GetItemType(symlinkToFile)
-	expect(t, e.None(),
-		`GetItemType(symlinkToFile): expected ec.NoError, got '%v'`, e)
+	errstest.NoErr(t, e, `GetItemType(symlinkToFile)`)
 	expect(t, r == fu.TYPE_SYMLINK,
 		`GetItemType(symlinkToFile): expected TYPE_SYMLINK, got '%v'`, r)
 
@@ -185,33 +188,28 @@ func TestFileExists(t *testing.T) {
 	printf("* TestFileExists(): using temp dir '%s'\n", globalTmpDir)
 	// When passing existing file, should return (true, no error)
 	r, e := fu.FileExists(existingFile)
-	expect(t, e.None(),
-		`fu.FileExists(existingFile): returned error '%v'`, e)
+	errstest.NoErr(t, e, `fu.FileExists(existingFile)`)
 	expect(t, r, "fu.FileExists(existingFile): returned false")
 
 	// When passing non-existing file, should return (false, no error)
 	r, e = fu.FileExists(nonExistingPath)
-	expect(t, e.None(),
-		`fu.FileExists(nonExistingPath): returned error '%v'`, e)
+	errstest.NoErr(t, e, `fu.FileExists(nonExistingPath)`)
 	expect(t, !r, `fu.FileExists(nonExistingPath): returned true`)
 
 	// When passing empty string, should return (false, no error)
 	r, e = fu.FileExists("")
-	expect(t, e.None(),
-		`fu.FileExists(''): returned unexpected error '%v'`, e)
+	errstest.NoErr(t, e, `fu.FileExists('')`)
 	expect(t, !r, `fu.FileExists(''): returned true`)
 
 	// When passing existing directory, should return (false, ec.Type)
 	r, e = fu.FileExists(testDirTreeRoot)
-	expect(t, e.Eq(ec.Type),
-		`fu.FileExists(existingDir): didn't return ec.Type error, got '%v'`, e)
+	errstest.Code(t, e, ec.Type, `fu.FileExists(existingDir)`)
 	expect(t, !r, "fu.FileExists(existingDir): returned true")
 
 	// UNIX-ONLY
 	// When passing symlink, should return (true, no error)
 	r, e = fu.FileExists(symlinkToFile)
-	expect(t, e.None(),
-		`fu.FileExists(symlinkToFile): returned error '%v'`, e)
+	errstest.NoErr(t, e, `fu.FileExists(symlinkToFile)`)
 	expect(t, r, "fu.FileExists(symlinkToFile): returned false")
 
 }
@@ -224,37 +222,27 @@ func TestCreateTextFile(t *testing.T) {
 	// Normally should create a file with given contents and return NoError
 	path := join(tmpDir, "text_file.txt")
 	e := fu.CreateTextFile(path, "test", false)
-	expect(t, e.None(),
-		`fu.CreateTextFile("text_file.txt", ..., false): `+
-			`NOT returned ec.NoError, got '%v'`, e)
+	errstest.NoErr(t, e, `fu.CreateTextFile("text_file.txt", ..., false)`)
 	exists, e := fu.FileExists(path)
-	expect(t, e.None())
+	errstest.NoErr(t, e)
 	expect(t, exists) // TODO: verify file contents
 
 	// When insufficient permissions should return ec.PermissionDenied
 	e = fu.CreateTextFile("/dummy.txt", "test", false)
-	expect(t, e.Eq(ec.PermissionDenied),
-		`fu.CreateTextFile("/dummy.txt", ..., false): `+
-			`NOT returned ec.PermissionDenied, got '%v'`, e)
+	errstest.Code(t, e, ec.PermissionDenied, `fu.CreateTextFile("/dummy.txt", ..., false)`)
 
 	// When file already exists should return ec.AlreadyExists
 	e = fu.CreateTextFile(path, "test", false)
-	expect(t, e.Eq(ec.AlreadyExists),
-		`fu.CreateTextFile(path, ..., false): `+
-			`NOT returned ec.AlreadyExists, got '%v'`, e)
+	errstest.Code(t, e, ec.AlreadyExists, `fu.CreateTextFile(path, ..., false)`)
 
 	// When file already exists and overwrite=true should return ec.NoError
 	e = fu.CreateTextFile(path, "test2", true)
-	expect(t, e.None(),
-		`fu.CreateTextFile(path, ..., true): `+
-			`NOT returned ec.NoError, got '%v'`, e)
+	errstest.NoErr(t, e, `fu.CreateTextFile(path, ..., true)`)
 
 	// When destination already exists but is a directory
 	// and overwrite=true, should return ec.Type
 	e = fu.CreateTextFile(tmpDir, "test3", true)
-	expect(t, e.Eq(ec.Type),
-		`fu.CreateTextFile(tmpDir, ..., true): `+
-			`NOT returned ec.Type, got '%v'`, e)
+	errstest.Code(t, e, ec.Type, `fu.CreateTextFile(tmpDir, ..., true)`)
 }
 
 func TestCopy(t *testing.T) {
@@ -266,9 +254,7 @@ func TestCopy(t *testing.T) {
 	// should return ec.NotFound.
 	shouldNotExist := join(localTmpDir, "should_not_exist.txt")
 	e := fu.Copy(nonExistingPath, shouldNotExist)
-	expect(t, e.Eq(ec.NotFound),
-		`Copy(nonExistingPath, shouldNotExist): `+
-			`expected ec.NotFound, got '%v'`, e)
+	errstest.Code(t, e, ec.NotFound, `Copy(nonExistingPath, shouldNotExist)`)
 	dest1Exists, e := fu.FileExists(shouldNotExist)
 	expect(t, !dest1Exists && e.None())
 
@@ -276,9 +262,7 @@ func TestCopy(t *testing.T) {
 	// should return NoError.
 	testdataCopy1 := join(localTmpDir, "testdata_copy_1")
 	e = fu.Copy(testDirTreeRoot, testdataCopy1)
-	expect(t, e.None(),
-		`Copy(testdataSrcDir, testdataDestDir): `+
-			`expected ec.NoError, got '%v'`, e)
+	errstest.NoErr(t, e, `Copy(testdataSrcDir, testdataDestDir)`)
 	testdataDestDirExists, e := fu.DirExists(testdataCopy1)
 	expect(t, testdataDestDirExists && e.None())
 	intact := fstestutils.AssertAllSourceItemsConsistent(testdataCopy1)
@@ -287,22 +271,17 @@ func TestCopy(t *testing.T) {
 	// Create a text file to be used in subsequent tests
 	anotherExistingFile := join(localTmpDir, "another_existing_file.txt")
 	e = fu.CreateTextFile(anotherExistingFile, "another_existing_file", false)
-	expect(t, e.None(),
-		`Copy(): fu.CreateTextFile(anotherExistingFile, ...) failed '%v'`, e)
+	errstest.NoErr(t, e, `Copy(): fu.CreateTextFile(anotherExistingFile, ...)`)
 
 	// When src is a file, dest is an existing file and using default options,
 	// should return ec.AlreadyExists.
 	e = fu.Copy(existingFile, anotherExistingFile)
-	expect(t, e.Eq(ec.AlreadyExists),
-		`Copy(existingFile, anotherExistingFile): `+
-			`expected ec.AlreadyExists, got '%v'`, e)
+	errstest.Code(t, e, ec.AlreadyExists, `Copy(existingFile, anotherExistingFile)`)
 
 	// When src is a file, dest is an existing dir and using default options,
 	// should return ec.Type.
 	e = fu.Copy(existingFile, testdataCopy1)
-	expect(t, e.Eq(ec.Type),
-		`Copy(existingFile, testdataDestDir): `+
-			`expected ec.Type, got '%v'`, e)
+	errstest.Code(t, e, ec.Type, `Copy(existingFile, testdataDestDir)`)
 
 	// Test custom Skip function.
 	// When src is a dir, dest not exists and skip function specified,
@@ -316,9 +295,7 @@ func TestCopy(t *testing.T) {
 			return false, nil
 		},
 	})
-	expect(t, e.None(),
-		`Copy(testdataSrcDir, ..., Skip dir_a): `+
-			`expected NoError, got '%v'`, e)
+	errstest.NoErr(t, e, `Copy(testdataSrcDir, ..., Skip dir_a)`)
 	testdataWithSkipDirExists, e := fu.DirExists(testdataWithSkipDir)
 	expect(t, testdataWithSkipDirExists && e.None())
 	dir_a_path := join(testdataWithSkipDir, "dir_a")
@@ -333,12 +310,12 @@ func TestCopy(t *testing.T) {
 	// merge dest should be intact.
 	mdrMerge := join(localTmpDir, "mdrMerge")
 	e = fstestutils.CreatePreExistingDestination(mdrMerge)
-	expect(t, e.None())
+	errstest.NoErr(t, e)
 	overwriteOptions := fu.CopyOptions{
 		OverwriteMode: fu.MERGE,
 	}
 	e = fu.Copy(testDirTreeRoot, mdrMerge, overwriteOptions)
-	expect(t, e.None())
+	errstest.NoErr(t, e)
 	// All items unique to source must be copied to the dest.
 	consistent := fstestutils.AssertUniqueToSourceItemsConsistent(mdrMerge)
 	expect(t, consistent)
@@ -351,13 +328,13 @@ func TestCopy(t *testing.T) {
 	// merge dest should be intact.
 	mdrOverwriteIntersection := join(localTmpDir, "mdrOverwriteIntersection")
 	e = fstestutils.CreatePreExistingDestination(mdrOverwriteIntersection)
-	expect(t, e.None())
+	errstest.NoErr(t, e)
 
 	overwriteOptions = fu.CopyOptions{
 		OverwriteMode: fu.OVERWRITE_INTERSECTION,
 	}
 	e = fu.Copy(testDirTreeRoot, mdrOverwriteIntersection, overwriteOptions)
-	expect(t, e.None())
+	errstest.NoErr(t, e)
 	// The whole test dir tree must be consistent
 	consistent = fstestutils.AssertAllSourceItemsConsistent(mdrOverwriteIntersection)
 	expect(t, consistent)
@@ -370,18 +347,451 @@ func TestCopy(t *testing.T) {
 	// merge dest should no longer exist.
 	mdrOverwriteFull := join(localTmpDir, "mdrOverwriteFull")
 	e = fstestutils.CreatePreExistingDestination(mdrOverwriteFull)
-	expect(t, e.None())
+	errstest.NoErr(t, e)
 	overwriteOptions = fu.CopyOptions{
 		OverwriteMode: fu.OVERWRITE_FULL,
 	}
 	e = fu.Copy(testDirTreeRoot, mdrOverwriteFull, overwriteOptions)
-	expect(t, e.None())
+	errstest.NoErr(t, e)
 	// The whole test dir tree must be consistent
 	consistent = fstestutils.AssertAllSourceItemsConsistent(mdrOverwriteFull)
 	expect(t, consistent)
 	// Items unique to merge dest should no longer exist.
 	uniqueItemsIntact = fstestutils.AssertUniquePreExistingItemsConsistent(mdrOverwriteFull)
 	expect(t, !uniqueItemsIntact)
+
+	// When copying with fu.OVERWRITE_FULL fails, dest should be kept intact
+	// and no temporary items should be left.
+	mdrOverwriteFullError := join(localTmpDir, "mdrOverwriteFullError")
+	e = fstestutils.CreatePreExistingDestination(mdrOverwriteFullError)
+	errstest.NoErr(t, e)
+	overwriteOptions.Skip = func(string) (bool, error) { return false, os.ErrPermission }
+	e = fu.Copy(testDirTreeRoot, mdrOverwriteFullError, overwriteOptions)
+	errstest.Code(t, e, ec.PermissionDenied)
+	expect(t, fstestutils.AssertAllPreExistingItemsConsistent(mdrOverwriteFullError))
+	expect(t, noHiddenItems(t, localTmpDir))
+}
+
+// Returns true if dir contains no hidden items, e.g. temporary
+// items created when replacing dest.
+func noHiddenItems(t *testing.T, dir string) bool {
+	entries, err := os.ReadDir(dir)
+	require.Nil(t, err)
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCopyOptions(t *testing.T) {
+	localTmpDir := createTestDir("copy_options_test")
+	printf("* TestCopyOptions(): using temp dir '%s'\n", localTmpDir)
+	srcDirA := join(testDirTreeRoot, "dir_a")
+	linkTarget := func(path string) string {
+		target, err := os.Readlink(path)
+		require.Nil(t, err)
+		return target
+	}
+
+	// SYMLINK_SHALLOW keeps relative targets inside the copied tree
+	// and makes absolute the ones pointing outside of it.
+	dest := join(localTmpDir, "shallow_tree")
+	errstest.NoErr(t, fu.Copy(testDirTreeRoot, dest))
+	require.Equal(t, "../dir_b/b.txt", linkTarget(join(dest, "dir_a", "symlink_to_b.txt")))
+	dest = join(localTmpDir, "shallow_dir_a")
+	errstest.NoErr(t, fu.Copy(srcDirA, dest))
+	require.Equal(t, join(testDirTreeRoot, "dir_b", "b.txt"),
+		linkTarget(join(dest, "symlink_to_b.txt")))
+
+	// SYMLINK_UNMODIFIED copies the target as is.
+	dest = join(localTmpDir, "unmodified_dir_a")
+	errstest.NoErr(t, fu.Copy(srcDirA, dest, fu.CopyOptions{SymlinkMode: fu.SYMLINK_UNMODIFIED}))
+	require.Equal(t, "../dir_b/b.txt", linkTarget(join(dest, "symlink_to_b.txt")))
+
+	// SYMLINK_DEEP copies the contents of the target.
+	dest = join(localTmpDir, "deep_dir_a")
+	errstest.NoErr(t, fu.Copy(srcDirA, dest, fu.CopyOptions{SymlinkMode: fu.SYMLINK_DEEP}))
+	contents, e := fu.ReadTextFile(join(dest, "symlink_to_b.txt"))
+	errstest.NoErr(t, e)
+	require.Equal(t, "b.txt", contents)
+
+	// When symlinks form a loop in SYMLINK_DEEP mode,
+	// should return ecfs.SymlinkLoop.
+	loopDir := join(localTmpDir, "loop")
+	require.Nil(t, os.Mkdir(loopDir, 0755))
+	require.Nil(t, os.Symlink(".", join(loopDir, "self")))
+	e = fu.Copy(loopDir, join(localTmpDir, "loop_copy"),
+		fu.CopyOptions{SymlinkMode: fu.SYMLINK_DEEP})
+	errstest.Code(t, e, ecfs.SymlinkLoop)
+
+	// AddPermission, PreserveTimes, Sync and CopyBufferSize.
+	srcFile := join(localTmpDir, "times.txt")
+	errstest.NoErr(t, fu.CreateTextFile(srcFile, "times.txt", false))
+	require.Nil(t, os.Chmod(srcFile, 0600))
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.Nil(t, os.Chtimes(srcFile, mtime, mtime))
+	dest = join(localTmpDir, "times_copy.txt")
+	errstest.NoErr(t, fu.Copy(srcFile, dest, fu.CopyOptions{
+		AddPermission:  0040,
+		PreserveTimes:  true,
+		Sync:           true,
+		CopyBufferSize: 4,
+	}))
+	info, err := os.Stat(dest)
+	require.Nil(t, err)
+	require.Equal(t, os.FileMode(0640), info.Mode().Perm())
+	require.True(t, info.ModTime().Equal(mtime))
+	contents, e = fu.ReadTextFile(dest)
+	errstest.NoErr(t, e)
+	require.Equal(t, "times.txt", contents)
+}
+
+func TestCopyContext(t *testing.T) {
+	localTmpDir := createTestDir("copy_context_test")
+	printf("* TestCopyContext(): using temp dir '%s'\n", localTmpDir)
+
+	// The last progress report should have empty Path
+	// and match the totals of the pre-scan.
+	var last fu.CopyProgress
+	reports := 0
+	e := fu.CopyContext(context.Background(), testDirTreeRoot,
+		join(localTmpDir, "progress"), fu.CopyOptions{
+			PreScan:  true,
+			Progress: func(p fu.CopyProgress) { last = p; reports++ },
+		})
+	errstest.NoErr(t, e)
+	require.Equal(t, "", last.Path)
+	require.Equal(t, len(fstestutils.SourceItemSet)+1, last.TotalItems)
+	require.Equal(t, last.TotalItems, last.Items)
+	require.Equal(t, last.TotalBytes, last.Bytes)
+	require.Greater(t, last.Bytes, int64(0))
+	require.Greater(t, reports, last.Items)
+
+	// When canceled, should stop and return ec.Canceled
+	// with the list of completed items.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dest := join(localTmpDir, "canceled")
+	e = fu.CopyContext(ctx, testDirTreeRoot, dest, fu.CopyOptions{
+		Progress: func(p fu.CopyProgress) {
+			if p.Items == 3 {
+				cancel()
+			}
+		},
+	})
+	errstest.Code(t, e, ec.Canceled)
+	completed, ok := e.Field(fu.KEY_COMPLETED)
+	require.True(t, ok)
+	require.Len(t, completed, 3)
+	for _, path := range completed.([]string) {
+		rel, err := filepath.Rel(testDirTreeRoot, path)
+		require.Nil(t, err)
+		exists, _, e := fu.PathExists(join(dest, rel))
+		errstest.NoErr(t, e)
+		expect(t, exists, "completed item '%s' not found in dest", rel)
+	}
+
+	// When the context is already canceled, nothing should be copied.
+	dest = join(localTmpDir, "not_started")
+	e = fu.CopyContext(ctx, testDirTreeRoot, dest)
+	errstest.Code(t, e, ec.Canceled)
+	exists, _, e := fu.PathExists(dest)
+	errstest.NoErr(t, e)
+	expect(t, !exists)
+}
+
+func TestCopyConcurrency(t *testing.T) {
+	localTmpDir := createTestDir("copy_concurrency_test")
+	printf("* TestCopyConcurrency(): using temp dir '%s'\n", localTmpDir)
+	parallel := fu.CopyOptions{Concurrency: 4, PreserveTimes: true}
+
+	dest := join(localTmpDir, "tree")
+	errstest.NoErr(t, fu.Copy(testDirTreeRoot, dest, parallel))
+	expect(t, fstestutils.AssertAllSourceItemsConsistent(dest))
+
+	// Permissions and times of dirs should be applied after their contents.
+	src := join(localTmpDir, "read_only")
+	require.Nil(t, os.MkdirAll(join(src, "sub"), 0755))
+	for i := 0; i < 8; i++ {
+		errstest.NoErr(t, fu.CreateTextFile(join(src, "sub", fmt.Sprintf("%d.txt", i)), "", false))
+	}
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.Nil(t, os.Chtimes(join(src, "sub"), mtime, mtime))
+	require.Nil(t, os.Chmod(join(src, "sub"), 0555))
+	defer os.Chmod(join(src, "sub"), 0755)
+	dest = join(localTmpDir, "read_only_copy")
+	errstest.NoErr(t, fu.Copy(src, dest, parallel))
+	defer os.Chmod(join(dest, "sub"), 0755)
+	info, err := os.Stat(join(dest, "sub"))
+	require.Nil(t, err)
+	require.Equal(t, os.FileMode(0555), info.Mode().Perm())
+	require.True(t, info.ModTime().Equal(mtime))
+
+	// Errors of workers should be collected into MultiErr.
+	src = join(localTmpDir, "files")
+	dest = join(localTmpDir, "dirs")
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("%d", i)
+		errstest.NoErr(t, fu.CreateTextFile(join(src, name), name, false))
+		require.Nil(t, os.MkdirAll(join(dest, name), 0755))
+	}
+	e := fu.Copy(src, dest, fu.CopyOptions{
+		Concurrency:   4,
+		OverwriteMode: fu.OVERWRITE_INTERSECTION,
+	})
+	errstest.Code(t, e, ec.Type)
+	var me *errs.MultiErr
+	require.True(t, errors.As(e, &me))
+	require.GreaterOrEqual(t, me.Len(), 1)
+
+	// When canceled, errs.KeyPath should be the interrupted file, not src.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupted := join(src, "3")
+	e = fu.CopyContext(ctx, src, join(localTmpDir, "canceled"), fu.CopyOptions{
+		Concurrency: 4,
+		Progress: func(p fu.CopyProgress) {
+			if p.Path == interrupted {
+				cancel()
+			}
+		},
+	})
+	errstest.Code(t, e, ec.Canceled)
+	path, ok := e.Field(errs.KeyPath)
+	require.True(t, ok)
+	require.Equal(t, src, filepath.Dir(path.(string)))
+}
+
+func TestCopyStrategy(t *testing.T) {
+	localTmpDir := createTestDir("copy_strategy_test")
+	printf("* TestCopyStrategy(): using temp dir '%s'\n", localTmpDir)
+	srcFile := join(localTmpDir, "src.txt")
+	srcContents := strings.Repeat("copy strategy\n", 1000)
+	errstest.NoErr(t, fu.CreateTextFile(srcFile, srcContents, false))
+
+	// Copies srcFile into dir with specified strategy,
+	// returns the method reported by the progress.
+	copyWith := func(dir string, strategy fu.CopyStrategy) (fu.CopyMethod, errs.Err) {
+		dest := join(dir, fmt.Sprintf("dest_%d.txt", strategy))
+		method := fu.METHOD_UNKNOWN
+		e := fu.Copy(srcFile, dest, fu.CopyOptions{
+			Strategy: strategy,
+			Progress: func(p fu.CopyProgress) {
+				if p.Method != fu.METHOD_UNKNOWN {
+					method = p.Method
+				}
+			},
+		})
+		if e.None() {
+			contents, e := fu.ReadTextFile(dest)
+			errstest.NoErr(t, e)
+			require.Equal(t, srcContents, contents)
+		}
+		return method, e
+	}
+
+	method, e := copyWith(localTmpDir, fu.COPY_USERSPACE)
+	errstest.NoErr(t, e)
+	require.Equal(t, fu.METHOD_USERSPACE, method)
+
+	method, e = copyWith(localTmpDir, fu.COPY_AUTO)
+	errstest.NoErr(t, e)
+	require.NotEqual(t, fu.METHOD_UNKNOWN, method)
+
+	// Reflinks depend on the file system of the temp dir.
+	method, e = copyWith(localTmpDir, fu.COPY_REFLINK)
+	if e.Some() {
+		errstest.Code(t, e, ec.Unsupported)
+	} else {
+		require.Equal(t, fu.METHOD_REFLINK, method)
+	}
+	method, e = copyWith(localTmpDir, fu.COPY_REFLINK_OR_COPY)
+	errstest.NoErr(t, e)
+	require.Contains(t, []fu.CopyMethod{fu.METHOD_REFLINK, fu.METHOD_USERSPACE}, method)
+
+	method, e = copyWith(localTmpDir, fu.COPY_KERNEL)
+	if runtime.GOOS == "linux" {
+		errstest.NoErr(t, e)
+		require.Contains(t, []fu.CopyMethod{fu.METHOD_COPY_FILE_RANGE, fu.METHOD_SENDFILE}, method)
+	} else {
+		errstest.Code(t, e, ec.Unsupported)
+	}
+
+	// IGU_FU_REFLINK_DIR may point to a dir on a file system
+	// that supports reflinks (btrfs, XFS), e.g. mounted from a loop device.
+	if dir := os.Getenv("IGU_FU_REFLINK_DIR"); dir != "" {
+		srcFile = join(dir, "igu_fu_reflink_src.txt")
+		errstest.NoErr(t, fu.CreateTextFile(srcFile, srcContents, true))
+		defer os.Remove(srcFile)
+		dir = join(dir, "igu_fu_reflink_test")
+		defer os.RemoveAll(dir)
+		for _, strategy := range []fu.CopyStrategy{fu.COPY_AUTO, fu.COPY_REFLINK} {
+			method, e = copyWith(dir, strategy)
+			errstest.NoErr(t, e)
+			require.Equal(t, fu.METHOD_REFLINK, method)
+		}
+	}
+}
+
+func TestMove(t *testing.T) {
+	localTmpDir := createTestDir("move_test")
+	printf("* TestMove(): using temp dir '%s'\n", localTmpDir)
+	newSrc := func(name string) string {
+		src := join(localTmpDir, name)
+		errstest.NoErr(t, fstestutils.CreateSourceDirTree(src))
+		return src
+	}
+	gone := func(path string) bool {
+		exists, _, e := fu.PathExists(path)
+		errstest.NoErr(t, e)
+		return !exists
+	}
+
+	// When src does not exist, should return ec.NotFound.
+	e := fu.Move(nonExistingPath, join(localTmpDir, "dest"))
+	errstest.Code(t, e, ec.NotFound)
+
+	// When dest does not exist, src should be renamed into dest.
+	src := newSrc("rename_src")
+	dest := join(localTmpDir, "parent_not_exists", "rename_dest")
+	errstest.NoErr(t, fu.Move(src, dest))
+	expect(t, gone(src))
+	expect(t, fstestutils.AssertAllSourceItemsConsistent(dest))
+	// Symlinks inside the moved tree keep relative targets.
+	target, err := os.Readlink(join(dest, "dir_a", "symlink_to_b.txt"))
+	require.Nil(t, err)
+	require.Equal(t, "../dir_b/b.txt", target)
+
+	// When a dir is renamed on its own, relative symlinks pointing
+	// outside of it should be made absolute as Copy() does.
+	src = newSrc("rename_dir_a_src")
+	dest = join(localTmpDir, "rename_dir_a_dest")
+	errstest.NoErr(t, fu.Move(join(src, "dir_a"), dest))
+	expect(t, gone(join(src, "dir_a")))
+	target, err = os.Readlink(join(dest, "symlink_to_b.txt"))
+	require.Nil(t, err)
+	require.Equal(t, join(src, "dir_b", "b.txt"), target)
+	contents, e := fu.ReadTextFile(join(dest, "symlink_to_b.txt"))
+	errstest.NoErr(t, e)
+	require.Equal(t, "b.txt", contents)
+	// With SYMLINK_UNMODIFIED the targets are kept as is.
+	src = newSrc("rename_dir_a_unmodified_src")
+	dest = join(localTmpDir, "rename_dir_a_unmodified_dest")
+	errstest.NoErr(t, fu.Move(join(src, "dir_a"), dest,
+		fu.CopyOptions{SymlinkMode: fu.SYMLINK_UNMODIFIED}))
+	target, err = os.Readlink(join(dest, "symlink_to_b.txt"))
+	require.Nil(t, err)
+	require.Equal(t, "../dir_b/b.txt", target)
+
+	// When dest exists and using default options, should return ec.AlreadyExists.
+	src = newSrc("existing_src")
+	e = fu.Move(src, dest)
+	errstest.Code(t, e, ec.AlreadyExists)
+	// When dest exists but has different type, should return ec.Type.
+	e = fu.Move(join(src, "test.txt"), dest)
+	errstest.Code(t, e, ec.Type)
+
+	// When src and dest are on different file systems,
+	// src should be copied into dest and removed.
+	restore := fu.SetRename(func(oldpath, newpath string) error {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EXDEV}
+	})
+	defer restore()
+	src = newSrc("cross_device_src")
+	dest = join(localTmpDir, "cross_device_dest")
+	errstest.NoErr(t, fu.Move(src, dest))
+	expect(t, gone(src))
+	expect(t, fstestutils.AssertAllSourceItemsConsistent(dest))
+
+	// When fu.MERGE specified, files common to source and dest
+	// should be kept in both places, the rest should be moved.
+	src = newSrc("merge_src")
+	dest = join(localTmpDir, "merge_dest")
+	errstest.NoErr(t, fstestutils.CreatePreExistingDestination(dest))
+	errstest.NoErr(t, fu.Move(src, dest, fu.CopyOptions{OverwriteMode: fu.MERGE}))
+	expect(t, fstestutils.AssertUniqueToSourceItemsConsistent(dest))
+	expect(t, fstestutils.AssertAllPreExistingItemsConsistent(dest))
+	expect(t, !gone(src))
+
+	// When fu.OVERWRITE_INTERSECTION specified, src should be moved
+	// into dest overwriting common files, items unique to dest
+	// should be intact.
+	src = newSrc("intersection_src")
+	dest = join(localTmpDir, "intersection_dest")
+	errstest.NoErr(t, fstestutils.CreatePreExistingDestination(dest))
+	errstest.NoErr(t, fu.Move(src, dest, fu.CopyOptions{
+		OverwriteMode: fu.OVERWRITE_INTERSECTION}))
+	expect(t, gone(src))
+	expect(t, fstestutils.AssertAllSourceItemsConsistent(dest))
+	expect(t, fstestutils.AssertUniquePreExistingItemsConsistent(dest))
+
+	// When fu.OVERWRITE_FULL specified, dest should be replaced by src.
+	src = newSrc("full_src")
+	dest = join(localTmpDir, "full_dest")
+	errstest.NoErr(t, fstestutils.CreatePreExistingDestination(dest))
+	errstest.NoErr(t, fu.Move(src, dest, fu.CopyOptions{OverwriteMode: fu.OVERWRITE_FULL}))
+	expect(t, gone(src))
+	expect(t, fstestutils.AssertAllSourceItemsConsistent(dest))
+	expect(t, !fstestutils.AssertUniquePreExistingItemsConsistent(dest))
+
+	// When moving with fu.OVERWRITE_FULL fails, src and dest
+	// should be kept intact.
+	src = newSrc("full_error_src")
+	dest = join(localTmpDir, "full_error_dest")
+	errstest.NoErr(t, fstestutils.CreatePreExistingDestination(dest))
+	e = fu.Move(src, dest, fu.CopyOptions{
+		OverwriteMode: fu.OVERWRITE_FULL,
+		Skip:          func(string) (bool, error) { return false, os.ErrPermission },
+	})
+	errstest.Code(t, e, ec.PermissionDenied)
+	expect(t, fstestutils.AssertAllSourceItemsConsistent(src))
+	expect(t, fstestutils.AssertAllPreExistingItemsConsistent(dest))
+	expect(t, noHiddenItems(t, localTmpDir))
+
+	// Skipped items should remain in src and not appear in dest.
+	restore()
+	src = newSrc("skip_src")
+	dest = join(localTmpDir, "skip_dest")
+	skipDirA := func(path string) (bool, error) {
+		return strings.Contains(path, "dir_a"), nil
+	}
+	errstest.NoErr(t, fu.Move(src, dest, fu.CopyOptions{Skip: skipDirA}))
+	expect(t, !gone(join(src, "dir_a")))
+	expect(t, gone(join(src, "test.txt")))
+	expect(t, gone(join(dest, "dir_a")))
+
+	// When Skip fails, nothing should be moved.
+	src = newSrc("skip_error_src")
+	dest = join(localTmpDir, "skip_error_dest")
+	e = fu.Move(src, dest, fu.CopyOptions{
+		Skip: func(string) (bool, error) { return false, os.ErrPermission },
+	})
+	errstest.Code(t, e, ec.PermissionDenied)
+	expect(t, gone(dest))
+	expect(t, fstestutils.AssertAllSourceItemsConsistent(src))
+
+	// Renamed items should get the current time as copied ones do,
+	// unless PreserveTimes is specified.
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for _, preserve := range []bool{false, true} {
+		src = newSrc(fmt.Sprintf("times_src_%v", preserve))
+		dest = join(localTmpDir, fmt.Sprintf("times_dest_%v", preserve))
+		require.Nil(t, os.Chtimes(join(src, "test.txt"), past, past))
+		errstest.NoErr(t, fu.Move(src, dest, fu.CopyOptions{PreserveTimes: preserve}))
+		info, err := os.Stat(join(dest, "test.txt"))
+		require.Nil(t, err)
+		require.Equal(t, preserve, info.ModTime().Equal(past))
+	}
+
+	// When fu.OVERWRITE_FULL specified and src can be renamed,
+	// dest should be replaced by src.
+	src = newSrc("full_rename_src")
+	errstest.NoErr(t, fu.Move(src, dest, fu.CopyOptions{OverwriteMode: fu.OVERWRITE_FULL}))
+	expect(t, gone(src))
+	expect(t, fstestutils.AssertAllSourceItemsConsistent(dest))
+	expect(t, noHiddenItems(t, localTmpDir))
 }
 
 // Returns true when a==b
@@ -540,3 +950,45 @@ func TestFsItemType(t *testing.T) {
 			"string representation expected to have non-zero length")
 	}
 }
+
+func TestCopyMethod(t *testing.T) {
+	require.Equal(t, "METHOD_REFLINK", fu.METHOD_REFLINK.String())
+	require.Equal(t, "CopyMethod(42)", fu.CopyMethod(42).String())
+}
+
+// Creates a tree of many small files to benchmark copying.
+func createBenchmarkTree(b *testing.B, root string) {
+	for d := 0; d < 20; d++ {
+		dir := join(root, fmt.Sprintf("dir_%d", d))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			b.Fatal(err)
+		}
+		for f := 0; f < 50; f++ {
+			path := join(dir, fmt.Sprintf("file_%d.txt", f))
+			if err := os.WriteFile(path, []byte(strings.Repeat("x", 4096)), 0644); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// Copies a tree of 1000 files of 4KB sequentially and in parallel.
+func BenchmarkCopy(b *testing.B) {
+	tmpDir := b.TempDir()
+	src := join(tmpDir, "src")
+	createBenchmarkTree(b, src)
+	for _, concurrency := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("Concurrency%d", concurrency), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				dest := join(tmpDir, fmt.Sprintf("dest_%d_%d", concurrency, i))
+				e := fu.Copy(src, dest, fu.CopyOptions{Concurrency: concurrency})
+				if e.Some() {
+					b.Fatal(e)
+				}
+				b.StopTimer()
+				os.RemoveAll(dest)
+				b.StartTimer()
+			}
+		})
+	}
+}
@@ -25,18 +25,18 @@ import (
 // TODO: implement TYPE_NAMED_PIPE
 func GetItemType(path string) (FsItemType, Err) {
 	if path == "" {
-		return TYPE_UNKNOWN, Err{Code: ec.NotFound}
+		return TYPE_UNKNOWN, Err{Code: ec.NotFound}.With(Path(path))
 	}
 	info, err := os.Lstat(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return TYPE_UNKNOWN, Err{Code: ec.NotFound, Cause: err}
+			return TYPE_UNKNOWN, Err{Code: ec.NotFound, Cause: err}.With(Path(path))
 		} else if os.IsPermission(err) {
-			return TYPE_UNKNOWN, Err{Code: ec.PermissionDenied, Cause: err}
+			return TYPE_UNKNOWN, Err{Code: ec.PermissionDenied, Cause: err}.With(Path(path))
 		} else if os.IsTimeout(err) {
-			return TYPE_UNKNOWN, Err{Code: ec.TimedOut, Cause: err}
+			return TYPE_UNKNOWN, Err{Code: ec.TimedOut, Cause: err}.With(Path(path))
 		} else {
-			return TYPE_UNKNOWN, Err{Code: ec.Other, Cause: err}
+			return TYPE_UNKNOWN, Err{Code: ec.Other, Cause: err}.With(Path(path))
 		}
 	}
 	if info.IsDir() {
@@ -67,3 +67,11 @@ func GetItemType(path string) (FsItemType, Err) {
 		return TYPE_FILE, NoError
 	}
 }
+
+// Creates a named pipe at path with specified permissions.
+func createNamedPipe(path string, mode os.FileMode) Err {
+	if err := syscall.Mkfifo(path, uint32(mode.Perm())); err != nil {
+		return FromError(err)
+	}
+	return NoError
+}
@@ -4,6 +4,8 @@ package fu
 
 import (
 	"os"
+	"syscall"
+	"time"
 
 	"github.com/iotanbo/igu/pkg/ec"
 
@@ -20,18 +22,18 @@ import (
 // 	ec.Other if other error(s) occurred.
 func GetItemType(path string) (FsItemType, Err) {
 	if path == "" {
-		return TYPE_UNKNOWN, Err{Code: ec.NotFound}
+		return TYPE_UNKNOWN, Err{Code: ec.NotFound}.With(Path(path))
 	}
 	info, err := os.Stat(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return TYPE_UNKNOWN, Err{Code: ec.NotFound, Cause: err}
+			return TYPE_UNKNOWN, Err{Code: ec.NotFound, Cause: err}.With(Path(path))
 		} else if os.IsPermission(err) {
-			return TYPE_UNKNOWN, Err{Code: ec.PermissionDenied, Cause: err}
+			return TYPE_UNKNOWN, Err{Code: ec.PermissionDenied, Cause: err}.With(Path(path))
 		} else if os.IsTimeout(err) {
-			return TYPE_UNKNOWN, Err{Code: ec.TimedOut, Cause: err}
+			return TYPE_UNKNOWN, Err{Code: ec.TimedOut, Cause: err}.With(Path(path))
 		} else {
-			return TYPE_UNKNOWN, Err{Code: ec.Other, Cause: err}
+			return TYPE_UNKNOWN, Err{Code: ec.Other, Cause: err}.With(Path(path))
 		}
 	}
 	if info.IsDir() {
@@ -43,3 +45,16 @@ func GetItemType(path string) (FsItemType, Err) {
 		return TYPE_FILE, NoError
 	}
 }
+
+// Returns the last access time of the item with specified info.
+func accessTime(info os.FileInfo) time.Time {
+	if d, ok := info.Sys().(*syscall.Win32FileAttributeData); ok {
+		return time.Unix(0, d.LastAccessTime.Nanoseconds())
+	}
+	return info.ModTime()
+}
+
+// Named pipes can't be created on windows as file system items.
+func createNamedPipe(path string, mode os.FileMode) Err {
+	return Err{Code: ec.Unsupported, Msg: "named pipes"}
+}
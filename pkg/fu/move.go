@@ -0,0 +1,274 @@
+package fu
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecfs"
+
+	//lint:ignore ST1001 - for concise error handling.
+	. "github.com/iotanbo/igu/pkg/errs"
+)
+
+// Renames file system items, replaced in tests to simulate
+// moving between file systems.
+var rename = os.Rename
+
+// Move moves any kind of file system items (file, dir, symlink etc.)
+// into dest using specified options, see Copy() for their meaning.
+// The item is renamed if possible, which is atomic within
+// a single file system. If the rename can't produce the result
+// requested by options (e.g. dest exists and OverwriteMode is MERGE,
+// Skip is specified, symlinks are copied deeply) or src and dest
+// are on different file systems, the item is copied with Copy()
+// and the copied source items are removed after checking that
+// each of them is present in dest.
+// Either way symlinks get the same targets as with Copy(): in SYMLINK_SHALLOW
+// mode relative targets pointing outside of the moved tree are made absolute.
+//
+// With OVERWRITE_FULL, src is moved into a temporary sibling of dest
+// that replaces dest only if moving succeeded, see OVERWRITE_FULL.
+// Unless PreserveTimes is set, the times of moved items are set
+// to the current time, even if they were renamed.
+// Items that were not moved remain in src together with their parent dirs:
+// items skipped by Skip, and with MERGE, files that already exist in dest.
+// If copying fails, the items created in dest are removed;
+// files overwritten in dest (OVERWRITE_INTERSECTION) can't be restored.
+// Returns NoError if success. Otherwise:
+//	ec.NotFound // src not exists
+//	ec.AlreadyExists // dest exists and DestOverwriteMode is NO_OVERWRITE
+//	ec.Type // dest exists and has type different from src
+//	ec.InvalidData // copied item is missing in dest or its size differs from src
+//	ec.PermissionDenied
+//	ec.TimedOut
+//	...or other less common errors.
+// If the copy succeeded but the source could not be removed, the error
+// has Msg "copied but failed to remove source" and dest is kept intact.
+//
+// Usage example:
+//	// Move a directory, merging it into existing dest
+//	e := Move("/src", "/dest", CopyOptions{OverwriteMode: MERGE})
+func Move(src, dest string, options ...CopyOptions) Err {
+	var o CopyOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	srcExists, srcType, e := PathExists(src)
+	if e.Some() {
+		return e
+	}
+	if !srcExists {
+		return Err{Code: ec.NotFound}.With(Op("move"), Path(src))
+	}
+	destExists, destType, e := PathExists(dest)
+	if e.Some() {
+		return e
+	}
+	if destExists {
+		if srcType != destType {
+			return Err{Code: ec.Type, Msg: destType.String()}.With(Op("move"), Path(dest))
+		}
+		switch o.OverwriteMode {
+		case NO_OVERWRITE:
+			return Err{Code: ec.AlreadyExists}.With(Op("move"), Path(dest))
+		case OVERWRITE_FULL:
+			// Src is moved into a temporary sibling of dest first
+			// so that dest is kept intact if moving fails.
+			o.OverwriteMode = NO_OVERWRITE
+			return replaceDest(dest, "move", func(tmp string) Err {
+				return move(src, tmp, false, o)
+			})
+		}
+	}
+	return move(src, dest, destExists, o)
+}
+
+// Moves src into dest, renaming it if possible.
+func move(src, dest string, destExists bool, o CopyOptions) Err {
+	if !destExists && renameAllowed(o) {
+		// Copy creates missing parent dirs of dest, so does Move
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return FromError(err).With(Op("move"), Path(dest))
+		}
+		err := rename(src, dest)
+		if err == nil {
+			return fixRenamed(src, dest, o)
+		}
+		if e := FromError(err); e.Code != ecfs.CrossDevice {
+			return e.With(Op("move"), Path(src))
+		}
+	}
+	return moveByCopy(src, dest, destExists, o)
+}
+
+// Returns true if renaming gives the same result as copying with options o
+// into not existing dest, provided the renamed tree is then updated
+// with fixRenamed().
+func renameAllowed(o CopyOptions) bool {
+	return o.Skip == nil && o.SymlinkMode != SYMLINK_DEEP && o.AddPermission == 0
+}
+
+// Makes the tree renamed from src to dest look as if it was copied
+// with options o: in SYMLINK_SHALLOW mode, relative symlink targets
+// pointing outside of the tree are made absolute (otherwise they would
+// break at the new location); unless PreserveTimes is set,
+// the access and modification times of the items are set to now.
+func fixRenamed(src, dest string, o CopyOptions) Err {
+	if o.SymlinkMode != SYMLINK_SHALLOW && o.PreserveTimes {
+		return NoError
+	}
+	c := copier{srcRoot: src}
+	now := time.Now()
+	err := filepath.Walk(dest, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			if o.PreserveTimes {
+				return nil
+			}
+			return os.Chtimes(path, now, now)
+		}
+		if o.SymlinkMode != SYMLINK_SHALLOW {
+			return nil
+		}
+		target, err := os.Readlink(path)
+		if err != nil || filepath.IsAbs(target) {
+			return err
+		}
+		rel, err := filepath.Rel(dest, path)
+		if err != nil {
+			return err
+		}
+		newTarget := c.shallowTarget(filepath.Join(src, rel), target)
+		if newTarget == target {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		return os.Symlink(newTarget, path)
+	})
+	if err != nil {
+		e := FromError(err)
+		e.Msg = "moved but failed to update symlinks and times"
+		return e.With(Op("move"), Path(dest))
+	}
+	return NoError
+}
+
+// Source item to be moved by moveByCopy.
+type moveItem struct {
+	// Path relative to src.
+	rel  string
+	info os.FileInfo
+}
+
+// Copies src into dest, then removes the moved items from src.
+func moveByCopy(src, dest string, destExists bool, o CopyOptions) Err {
+	// Collect source items before copying together with
+	// the ones that already exist in dest.
+	var items []moveItem
+	existed := map[string]bool{}
+	kept := map[string]bool{} // not moved items and their parent dirs
+	keep := func(rel string) {
+		for ; rel != "." && !kept[rel]; rel = filepath.Dir(rel) {
+			kept[rel] = true
+		}
+		kept["."] = true
+	}
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if o.Skip != nil && rel != "." {
+			skip, err := o.Skip(path)
+			if err != nil {
+				return err
+			}
+			if skip {
+				keep(rel)
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+		if destExists {
+			if _, err := os.Lstat(filepath.Join(dest, rel)); err == nil {
+				existed[rel] = true
+				if o.OverwriteMode == MERGE && !info.IsDir() {
+					keep(rel)
+					return nil
+				}
+			}
+		}
+		items = append(items, moveItem{rel: rel, info: info})
+		return nil
+	})
+	if err != nil {
+		return FromError(err).With(Op("move"), Path(src))
+	}
+
+	if e := Copy(src, dest, o); e.Some() {
+		removeCopied(dest, destExists, items, existed)
+		return e
+	}
+	for _, item := range items {
+		if e := verifyMoved(filepath.Join(dest, item.rel), item.info); e.Some() {
+			removeCopied(dest, destExists, items, existed)
+			return e.With(Op("move"))
+		}
+	}
+
+	// Remove moved items from src, dirs after their contents.
+	for i := len(items) - 1; i >= 0; i-- {
+		if kept[items[i].rel] {
+			continue
+		}
+		path := filepath.Join(src, items[i].rel)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			e := FromError(err)
+			e.Msg = "copied but failed to remove source"
+			return e.With(Op("move"), Path(path))
+		}
+	}
+	return NoError
+}
+
+// Checks that the copy of source item with specified info exists at path.
+func verifyMoved(path string, info os.FileInfo) Err {
+	destInfo, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return Err{Code: ec.InvalidData, Msg: "copied item is missing", Cause: err}.
+			With(Path(path))
+	} else if err != nil {
+		return FromError(err).With(Path(path))
+	}
+	if info.Mode().IsRegular() && destInfo.Mode().IsRegular() &&
+		info.Size() != destInfo.Size() {
+		return Err{Code: ec.InvalidData, Msg: "size of copied file differs from source"}.
+			With(Path(path))
+	}
+	return NoError
+}
+
+// Removes the items created in dest by a failed copy.
+func removeCopied(dest string, destExisted bool, items []moveItem,
+	existed map[string]bool) {
+	if !destExisted {
+		os.RemoveAll(dest)
+		return
+	}
+	for _, item := range items {
+		if !existed[item.rel] {
+			os.RemoveAll(filepath.Join(dest, item.rel))
+		}
+	}
+}
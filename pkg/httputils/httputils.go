@@ -5,43 +5,125 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/iotanbo/igu/pkg/ec"
 	"github.com/iotanbo/igu/pkg/ecdef"
+	"github.com/iotanbo/igu/pkg/echttp"
 	//lint:ignore ST1001 - for concise error handling.
 	. "github.com/iotanbo/igu/pkg/errs"
 )
 
+// Key of the context field that holds the delay requested by the server
+// in the Retry-After header, the value is of type time.Duration.
+const KeyRetryAfter = "retry_after"
+
 // Download can download large files without risk
 // depleting memory.
 // Based on https://stackoverflow.com/a/33853856/3824328
+// Network failures are reported with ecnet codes
+// (e.g. ecnet.DNSFailure, ecnet.ConnectionRefused, ecnet.UntrustedCertificate)
+// or ec.TimedOut, the original error is kept as Cause.
+// Unsuccessful HTTP responses are reported by ErrFromResponse().
 func Download(url string, destPath string) Err {
 	// Create the file
 	out, err := os.Create(destPath)
 	if err != nil {
-		return FromError(err)
+		return FromError(err).With(Path(destPath))
 	}
 	defer out.Close()
 
 	// Get the data
 	resp, err := http.Get(url)
 	if err != nil {
-		return FromError(err)
+		return FromError(err).With(URL(url))
 	}
 	defer resp.Body.Close()
 
 	// Check server response
-	if resp.StatusCode != http.StatusOK {
-		return Err{
-			Code: ecdef.ErrCode(resp.StatusCode),
-			Msg:  fmt.Sprintf("bad status: %s", resp.Status),
-		}
+	if e := ErrFromResponse(resp); e.Some() {
+		return e
 	}
 
 	// Writer the body to file
 	_, err = io.Copy(out, resp.Body)
 	if err != nil {
-		return FromError(err)
+		return FromError(err).With(URL(url), Path(destPath))
 	}
 
 	return NoError
 }
+
+// ErrFromResponse converts an unsuccessful HTTP response into Err
+// whose code is the response status (see echttp package),
+// returns NoError if the status is 2xx.
+// The URL of the request and the delay from the Retry-After header
+// (if present and valid) are attached as context fields,
+// the latter can be read back with RetryAfter().
+// Usage example:
+//
+//	if e := ErrFromResponse(resp); e.Some() {
+//		if e.IsHTTPRetryable() {
+//			delay, _ := RetryAfter(e)
+//			// ... retry after the delay
+//		}
+//		return e
+//	}
+//
+// Otherwise returns errors:
+//	echttp.* // any HTTP status except 2xx;
+//	ec.InvalidData // status is out of 100..599 range.
+func ErrFromResponse(resp *http.Response) Err {
+	code := ecdef.ErrCode(resp.StatusCode)
+	if echttp.IsSuccess(code) {
+		return NoError
+	}
+	e := Err{Code: code}
+	if code < ecdef.HTTP_RANGE_BEGIN || code > ecdef.HTTP_RANGE_END {
+		e = Err{Code: ec.InvalidData, Msg: fmt.Sprintf("bad status: %s", resp.Status)}
+	}
+	if resp.Request != nil && resp.Request.URL != nil {
+		e = e.With(URL(resp.Request.URL.String()))
+	}
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		e = e.With(KV(KeyRetryAfter, d))
+	}
+	return e
+}
+
+// RetryAfter returns the delay requested by the server in the Retry-After
+// header of the response e was created from by ErrFromResponse(),
+// the cause chain of e is searched as well.
+func RetryAfter(e Err) (time.Duration, bool) {
+	v, ok := e.Field(KeyRetryAfter)
+	if !ok {
+		return 0, false
+	}
+	d, ok := v.(time.Duration)
+	return d, ok
+}
+
+// parseRetryAfter parses the value of Retry-After header which is either
+// a number of seconds or an HTTP date, a date in the past results in zero delay.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
@@ -0,0 +1,123 @@
+package httputils
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/echttp"
+	//lint:ignore ST1001 - for concise error handling.
+	. "github.com/iotanbo/igu/pkg/errs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrFromResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/busy":
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/file":
+			_, _ = w.Write([]byte("content"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/busy")
+	require.Nil(t, err)
+	resp.Body.Close()
+	e := ErrFromResponse(resp)
+	require.True(t, e.Code == echttp.ServiceUnavailable_503)
+	require.True(t, e.IsHTTPServerError() && e.IsHTTPRetryable())
+	d, ok := RetryAfter(e)
+	require.True(t, ok)
+	require.Equal(t, 120*time.Second, d)
+	url, ok := e.Field("url")
+	require.True(t, ok)
+	require.Equal(t, srv.URL+"/busy", url)
+
+	dest := filepath.Join(t.TempDir(), "file.txt")
+	e = Download(srv.URL+"/missing", dest)
+	require.True(t, e.Code == echttp.NotFound_404)
+	require.True(t, e.IsHTTPClientError() && !e.IsHTTPRetryable())
+	_, ok = RetryAfter(e)
+	require.False(t, ok)
+
+	e = Download(srv.URL+"/file", dest)
+	require.True(t, e.None(), "%v", e)
+	data, err := os.ReadFile(dest)
+	require.Nil(t, err)
+	require.Equal(t, "content", string(data))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, 10, 21, 7, 28, 0, 0, time.UTC)
+	var testData = []struct {
+		value string
+		d     time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"0", 0, true},
+		{" 5 ", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"Thu, 21 Oct 2021 07:30:00 GMT", 2 * time.Minute, true},
+		{"Thu, 21 Oct 2021 07:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, td := range testData {
+		d, ok := parseRetryAfter(td.value, now)
+		require.Equal(t, td.ok, ok, "value: %q", td.value)
+		require.Equal(t, td.d, d, "value: %q", td.value)
+	}
+}
+
+func TestWriteProblem(t *testing.T) {
+	inner := Err{Code: ec.NotFound, Cause: fs.ErrNotExist}.With(Path("/etc/app.conf"))
+	e := Err{Code: ec.NotFound, Msg: "config"}.With(Op("load"))
+	e.Cause = inner
+	w := httptest.NewRecorder()
+	require.Equal(t, NoError, WriteProblem(w, e))
+	require.Equal(t, 404, w.Code)
+	require.Equal(t, PROBLEM_CONTENT_TYPE, w.Header().Get("Content-Type"))
+	var p Problem
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), &p))
+	require.Equal(t, Problem{
+		Type:   "about:blank",
+		Title:  "ec.NotFound",
+		Status: 404,
+		Detail: "config",
+		Code:   "ec.NotFound",
+		Context: map[string]interface{}{
+			"op": "load",
+		},
+	}, p)
+
+	// The whole chain, including the path of the wrapped cause,
+	// is exposed only on request
+	full := NewProblem(e, ProblemOptions{FullChain: true})
+	require.Equal(t, e.Error(), full.Detail)
+	require.Equal(t, map[string]interface{}{
+		"op":   "load",
+		"path": "/etc/app.conf",
+	}, full.Context)
+	require.Equal(t, "", NewProblem(inner).Detail)
+
+	// HTTP errors keep their status
+	w = httptest.NewRecorder()
+	require.Equal(t, NoError, WriteProblem(w, Err{Code: echttp.TooManyRequests_429}))
+	require.Equal(t, 429, w.Code)
+
+	// Values that can't be encoded are reported
+	w = httptest.NewRecorder()
+	e = WriteProblem(w, Err{Code: ec.Other}.With(KV("ch", make(chan int))))
+	require.True(t, e.Code == ec.InvalidData)
+}
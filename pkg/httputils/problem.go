@@ -0,0 +1,115 @@
+package httputils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecdef"
+	//lint:ignore ST1001 - for concise error handling.
+	. "github.com/iotanbo/igu/pkg/errs"
+)
+
+// Content type of problem details, see RFC 7807.
+const PROBLEM_CONTENT_TYPE = "application/problem+json"
+
+// Problem is a "problem details" object of RFC 7807
+// extended with the symbolic error code and the context fields.
+type Problem struct {
+	// URI reference that identifies the problem type.
+	Type string `json:"type"`
+	// Short summary of the problem type, the description of the error code.
+	Title string `json:"title"`
+	// HTTP status code, see Err.HTTPStatus().
+	Status int `json:"status"`
+	// Explanation specific to this occurrence of the problem.
+	// Msg of the outermost error, or the text of the whole chain
+	// if ProblemOptions.FullChain is set.
+	Detail string `json:"detail,omitempty"`
+	// URI reference that identifies this occurrence of the problem (optional).
+	Instance string `json:"instance,omitempty"`
+	// Symbolic name of the error code, e.g. "ec.NotFound".
+	Code string `json:"code,omitempty"`
+	// Context fields of the error, or of the whole chain
+	// if ProblemOptions.FullChain is set (an outer field hides
+	// the inner ones with the same key).
+	Context map[string]interface{} `json:"context,omitempty"`
+}
+
+// ProblemOptions specify how problem details are created from an error.
+type ProblemOptions struct {
+	// If true, the detail is the text of the whole chain (e.Error())
+	// and the context holds the fields of all errors of the chain.
+	// It may contain OS error text, file paths and other internals,
+	// so enable it for debugging only.
+	FullChain bool
+}
+
+// NewProblem creates problem details from the chain of e:
+// the status is e.HTTPStatus(), the title is the description of e.Code,
+// the detail is e.Msg and the context holds the fields of e;
+// the causes are not exposed unless ProblemOptions.FullChain is set.
+func NewProblem(e Err, options ...ProblemOptions) Problem {
+	var o ProblemOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	p := Problem{
+		Type:   "about:blank",
+		Title:  ecdef.CodeToString(e.Code),
+		Status: e.HTTPStatus(),
+		Detail: e.Msg,
+		Code:   e.Name(),
+	}
+	if o.FullChain {
+		p.Detail = e.Error()
+	}
+	var err error = e
+	for err != nil {
+		if link, ok := AsErr(err); ok {
+			for _, f := range link.Fields() {
+				if _, exists := p.Context[f.Key]; !exists {
+					if p.Context == nil {
+						p.Context = map[string]interface{}{}
+					}
+					p.Context[f.Key] = f.Value
+				}
+			}
+		}
+		if !o.FullChain {
+			break
+		}
+		err = errors.Unwrap(err)
+	}
+	return p
+}
+
+// WriteProblem writes problem details of e (see NewProblem() and ProblemOptions)
+// as the response with PROBLEM_CONTENT_TYPE content type and
+// the status code of the problem.
+// Returns ec.InvalidData if a context value can't be encoded as JSON,
+// in this case nothing is written.
+// Usage example:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//		data, e := fu.ReadTextFile(path)
+//		if e.Some() {
+//			WriteProblem(w, e)
+//			return
+//		}
+//		// ...
+//	}
+func WriteProblem(w http.ResponseWriter, e Err, options ...ProblemOptions) Err {
+	p := NewProblem(e, options...)
+	body, err := json.Marshal(p)
+	if err != nil {
+		return Err{Code: ec.InvalidData, Msg: "can't encode problem details", Cause: err}
+	}
+	w.Header().Set("Content-Type", PROBLEM_CONTENT_TYPE)
+	w.WriteHeader(p.Status)
+	if _, err = w.Write(body); err != nil {
+		return FromError(err)
+	}
+	return NoError
+}
@@ -0,0 +1,20 @@
+/*
+Package lg is a small leveled logger used by IGU packages
+(sh, linuser, zip...) for diagnostic output.
+Features:
+  - levels DEBUG, INFO, WARN and ERROR, messages below the level
+    of a logger are discarded cheaply;
+  - the default logger writes INFO and higher to os.Stderr,
+    it can be replaced or silenced with SetDefault() and SetLevel();
+  - printf-style methods, a line per message.
+
+Output example:
+
+	2021/06/01 12:00:00 INFO linuser: executing 'groupadd test'
+
+Usage example:
+
+	lg.SetLevel(lg.DEBUG)
+	lg.Debugf("sh: executing '%s'", cmd)
+*/
+package lg
@@ -0,0 +1,133 @@
+package lg
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Level is the severity of a log message.
+type Level int32
+
+// Log levels in order of severity.
+const (
+	DEBUG Level = iota - 1
+	INFO
+	WARN
+	ERROR
+	// Discards all messages when set as the level of a logger.
+	OFF
+)
+
+// String returns the name of the level, e.g. "INFO".
+func (l Level) String() string {
+	switch l {
+	case DEBUG:
+		return "DEBUG"
+	case INFO:
+		return "INFO"
+	case WARN:
+		return "WARN"
+	case ERROR:
+		return "ERROR"
+	case OFF:
+		return "OFF"
+	}
+	return fmt.Sprintf("LEVEL(%d)", int32(l))
+}
+
+// ParseLevel converts a level name (case-insensitive) into Level,
+// returns false if the name is unknown.
+func ParseLevel(name string) (Level, bool) {
+	for l := DEBUG; l <= OFF; l++ {
+		if strings.EqualFold(name, l.String()) {
+			return l, true
+		}
+	}
+	return INFO, false
+}
+
+// Logger writes messages of the enabled levels to an io.Writer.
+// It is safe for concurrent use.
+type Logger struct {
+	level int32
+	out   *log.Logger
+}
+
+// New creates a Logger that writes messages of the specified level
+// and higher to w, each message is prefixed with date and time.
+func New(w io.Writer, level Level) *Logger {
+	return &Logger{level: int32(level), out: log.New(w, "", log.LstdFlags)}
+}
+
+// Level returns the current level of l.
+func (l *Logger) Level() Level { return Level(atomic.LoadInt32(&l.level)) }
+
+// SetLevel changes the level of l.
+func (l *Logger) SetLevel(level Level) { atomic.StoreInt32(&l.level, int32(level)) }
+
+// Enabled returns true if messages of specified level are written.
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.Level() && level < OFF
+}
+
+// Logf writes a message of specified level if the level is enabled.
+func (l *Logger) Logf(level Level, format string, args ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	l.out.Print(level.String() + " " + fmt.Sprintf(format, args...))
+}
+
+// Debugf writes a message of level DEBUG.
+func (l *Logger) Debugf(format string, args ...interface{}) { l.Logf(DEBUG, format, args...) }
+
+// Infof writes a message of level INFO.
+func (l *Logger) Infof(format string, args ...interface{}) { l.Logf(INFO, format, args...) }
+
+// Warnf writes a message of level WARN.
+func (l *Logger) Warnf(format string, args ...interface{}) { l.Logf(WARN, format, args...) }
+
+// Errorf writes a message of level ERROR.
+func (l *Logger) Errorf(format string, args ...interface{}) { l.Logf(ERROR, format, args...) }
+
+var defaultLogger atomic.Value // *Logger
+
+func init() {
+	defaultLogger.Store(New(os.Stderr, INFO))
+}
+
+// Default returns the logger used by IGU packages.
+func Default() *Logger { return defaultLogger.Load().(*Logger) }
+
+// SetDefault replaces the logger used by IGU packages,
+// e.g. to redirect their output. A nil logger discards all messages.
+func SetDefault(l *Logger) {
+	if l == nil {
+		l = New(io.Discard, OFF)
+	}
+	defaultLogger.Store(l)
+}
+
+// SetLevel changes the level of the default logger.
+func SetLevel(level Level) { Default().SetLevel(level) }
+
+// Logf writes a message of specified level with the default logger.
+func Logf(level Level, format string, args ...interface{}) {
+	Default().Logf(level, format, args...)
+}
+
+// Debugf writes a message of level DEBUG with the default logger.
+func Debugf(format string, args ...interface{}) { Default().Logf(DEBUG, format, args...) }
+
+// Infof writes a message of level INFO with the default logger.
+func Infof(format string, args ...interface{}) { Default().Logf(INFO, format, args...) }
+
+// Warnf writes a message of level WARN with the default logger.
+func Warnf(format string, args ...interface{}) { Default().Logf(WARN, format, args...) }
+
+// Errorf writes a message of level ERROR with the default logger.
+func Errorf(format string, args ...interface{}) { Default().Logf(ERROR, format, args...) }
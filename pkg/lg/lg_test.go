@@ -0,0 +1,61 @@
+package lg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, INFO)
+	l.Debugf("hidden %d", 1)
+	l.Infof("shown %d", 2)
+	l.Errorf("error %s", "3")
+	out := buf.String()
+	require.NotContains(t, out, "hidden")
+	require.Contains(t, out, "INFO shown 2\n")
+	require.Contains(t, out, "ERROR error 3\n")
+
+	buf.Reset()
+	l.SetLevel(DEBUG)
+	require.True(t, l.Enabled(DEBUG))
+	l.Debugf("visible")
+	require.Contains(t, buf.String(), "DEBUG visible\n")
+
+	buf.Reset()
+	l.SetLevel(OFF)
+	require.False(t, l.Enabled(ERROR))
+	l.Errorf("discarded")
+	require.Empty(t, buf.String())
+}
+
+func TestDefault(t *testing.T) {
+	saved := Default()
+	defer SetDefault(saved)
+
+	var buf bytes.Buffer
+	SetDefault(New(&buf, WARN))
+	Infof("info")
+	Warnf("warning")
+	require.NotContains(t, buf.String(), "info")
+	require.Contains(t, buf.String(), "WARN warning")
+
+	SetDefault(nil)
+	require.False(t, Default().Enabled(ERROR))
+}
+
+func TestParseLevel(t *testing.T) {
+	for _, l := range []Level{DEBUG, INFO, WARN, ERROR, OFF} {
+		parsed, ok := ParseLevel(l.String())
+		require.True(t, ok)
+		require.Equal(t, l, parsed)
+	}
+	l, ok := ParseLevel("warn")
+	require.True(t, ok)
+	require.Equal(t, WARN, l)
+	_, ok = ParseLevel("verbose")
+	require.False(t, ok)
+	require.Equal(t, "LEVEL(7)", Level(7).String())
+}
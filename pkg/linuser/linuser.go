@@ -6,6 +6,7 @@ import (
 
 	"github.com/iotanbo/igu/pkg/ec"
 	"github.com/iotanbo/igu/pkg/fu"
+	"github.com/iotanbo/igu/pkg/lg"
 	"github.com/iotanbo/igu/pkg/sh"
 
 	//"github.com/iotanbo/igu/pkg/sh"
@@ -27,6 +28,16 @@ type UserDescriptor struct {
 	IsSystem    bool // set to true if this is a system user (id < 1000)
 }
 
+// logf logs a message of the package with level INFO in verbose mode
+// and with level DEBUG otherwise.
+func logf(verbose bool, format string, args ...interface{}) {
+	level := lg.DEBUG
+	if verbose {
+		level = lg.INFO
+	}
+	lg.Logf(level, "linuser: "+format, args...)
+}
+
 func run(cmd string, verbose bool) Err {
 	// shell := "bash"
 	// args := []string{
@@ -34,13 +45,27 @@ func run(cmd string, verbose bool) Err {
 	// 	cmd,
 	// }
 	stdout, stderr, e := sh.ExecuteLine(cmd, 10000)
-	if verbose {
-		if len(stdout) > 0 {
-			fmt.Printf("'%s'\n", stdout)
-		}
+	if len(stdout) > 0 {
+		logf(verbose, "stdout: '%s'", stdout)
 	}
 	if e.Some() {
-		fmt.Printf("'%s'\n", stderr)
+		logf(verbose, "stderr: '%s'", stderr)
+	}
+	return commandError(e, stderr)
+}
+
+// Sets password of the user with chpasswd, the password is passed on stdin;
+// neither the password nor the output of chpasswd is logged.
+func setPassword(userName, password string) Err {
+	_, stderr, e := sh.ExecuteCmdInput("chpasswd", nil,
+		fmt.Sprintf("%s:%s\n", userName, password), 10000)
+	return commandError(e, stderr)
+}
+
+// Converts error of an executed command into Err with code
+// recognized from its stderr output.
+func commandError(e Err, stderr string) Err {
+	if e.Some() {
 		errLowerCase := strings.ToLower(stderr)
 		if strings.Contains(errLowerCase, "permission denied") {
 			return Err{Code: ec.PermissionDenied, Msg: stderr}
@@ -89,13 +114,14 @@ func UserExists(userName string) (bool, Err) {
 
 // CreateGroup creates a linux user group.
 // If groupId not specified, default system ID will be created.
-// `verbose` parameter allows printing additional info and stdout.
+// `verbose` parameter logs executed commands and their output
+// with level INFO instead of DEBUG, see package lg.
 //
 // Returned errors:
 //	ec.NoError // completed successfully
 //	ec.AlreadyExists // group already exists
 //	ec.ProcessExit // sub-process exited with non-zero error code
-//	ec.TimedOut // execution took longer than 10 seconds
+//	ec.DeadlineExceeded // execution took longer than 10 seconds
 //	ec.PermissionDenied
 // Other errors may be returned for other situations.
 //
@@ -112,28 +138,27 @@ func CreateGroup(groupName string, verbose bool, groupId ...int) Err {
 		return e
 	}
 	if alreadyExists {
-		return Err{Code: ec.AlreadyExists}
+		return Err{Code: ec.AlreadyExists}.With(UserGroup(groupName))
 	}
 	groupIdParams := ""
 	if gid != 0 {
 		groupIdParams = fmt.Sprintf("-g %d", gid)
 	}
 	cmd := fmt.Sprintf("groupadd %s %s", groupIdParams, groupName)
-	if verbose {
-		fmt.Printf("* CreateGroup: executing command '%s'\n", cmd)
-	}
+	logf(verbose, "CreateGroup: executing '%s'", cmd)
 	return run(cmd, verbose)
 }
 
 // DeleteGroup deletes a linux user group.
 // It will delete the group even if it is the primary group of a user.
-// `verbose` parameter allows printing additional info and stdout.
+// `verbose` parameter logs executed commands and their output
+// with level INFO instead of DEBUG, see package lg.
 //
 // Returned errors:
 //	ec.NoError // completed successfully
 //	ec.NotFound // group not found
 //	ec.ProcessExit // sub-process exited with non-zero error code
-//	ec.TimedOut // execution took longer than 10 seconds
+//	ec.DeadlineExceeded // execution took longer than 10 seconds
 //	ec.PermissionDenied
 // Other errors may be returned for other situations.
 //
@@ -146,12 +171,10 @@ func DeleteGroup(groupName string, verbose bool) Err {
 		return e
 	}
 	if !alreadyExists {
-		return Err{Code: ec.NotFound}
+		return Err{Code: ec.NotFound}.With(UserGroup(groupName))
 	}
 	cmd := fmt.Sprintf("groupdel -f %s", groupName)
-	if verbose {
-		fmt.Printf("* DeleteGroup: executing command '%s'\n", cmd)
-	}
+	logf(verbose, "DeleteGroup: executing '%s'", cmd)
 	return run(cmd, verbose)
 }
 
@@ -165,13 +188,13 @@ func DeleteGroup(groupName string, verbose bool) Err {
 //
 // Args:
 //	ud // UserDescriptor.
-//	verbose // verbose mode.
+//	verbose // log executed commands with level INFO instead of DEBUG.
 //
 // Returned errors:
 //	ec.NoError // completed successfully
 //	ec.AlreadyExists // user already exists
 //	ec.ProcessExit // sub-process exited with non-zero error code
-//	ec.TimedOut // execution took longer than 10 seconds
+//	ec.DeadlineExceeded // execution took longer than 10 seconds
 //	ec.PermissionDenied
 // Other errors may be returned for other situations.
 //
@@ -193,7 +216,7 @@ func CreateUser(
 		return e
 	}
 	if userExists {
-		return Err{Code: ec.AlreadyExists}
+		return Err{Code: ec.AlreadyExists}.With(User(ud.UserName))
 	}
 
 	// https://linux.die.net/man/8/useradd
@@ -236,20 +259,15 @@ func CreateUser(
 	cmd := fmt.Sprintf("useradd --no-log-init %s %s %s %s %s %s %s", sysUserParams, descParams, groupParams, homeDirParams,
 		shellParams, userParams, ud.UserName)
 
-	if verbose {
-		fmt.Printf("* CreateUser: executing command '%s'\n", cmd)
-	}
+	logf(verbose, "CreateUser: executing '%s'", cmd)
 	e = run(cmd, verbose)
 	if e.Some() {
-		return e
+		return e.With(User(ud.UserName))
 	}
 	// Set password if specified
 	if len(ud.Password) > 0 {
-		cmd = fmt.Sprintf("echo %s:%s | chpasswd", ud.UserName, ud.Password)
-		if verbose {
-			fmt.Printf("* CreateUser: setting user password.\n")
-		}
-		return run(cmd, verbose)
+		logf(verbose, "CreateUser: setting user password")
+		return setPassword(ud.UserName, ud.Password)
 	}
 	return NoError
 }
@@ -261,13 +279,14 @@ func CreateUser(
 // In case there are other users in this user's primary group,
 // the group will not be deleted, you have to do it manually.
 // `deleteHomeDir` allows to delete user's home directory.
-// `verbose` allows printing additional info and stdout.
+// `verbose` logs executed commands and their output
+// with level INFO instead of DEBUG, see package lg.
 //
 // Returned errors:
 //	ec.NoError // completed successfully
 //	ec.NotFound // user not found
 //	ec.ProcessExit // sub-process exited with non-zero error code
-//	ec.TimedOut // execution took longer than 10 seconds
+//	ec.DeadlineExceeded // execution took longer than 10 seconds
 //	ec.PermissionDenied
 // Other errors may be returned for other situations.
 //
@@ -280,7 +299,7 @@ func DeleteUser(userName string, deleteHomeDir bool, verbose bool) Err {
 		return e
 	}
 	if !alreadyExists {
-		return Err{Code: ec.NotFound}
+		return Err{Code: ec.NotFound}.With(User(userName))
 	}
 	removeHomeDirParams := ""
 	if deleteHomeDir {
@@ -288,9 +307,7 @@ func DeleteUser(userName string, deleteHomeDir bool, verbose bool) Err {
 		removeHomeDirParams = "-rf"
 	}
 	cmd := fmt.Sprintf("userdel %s %s", removeHomeDirParams, userName)
-	if verbose {
-		fmt.Printf("* DeleteUser: executing '%s'\n", cmd)
-	}
+	logf(verbose, "DeleteUser: executing '%s'", cmd)
 	return run(cmd, verbose)
 }
 
@@ -303,20 +320,14 @@ func ensureUserAndGroupExist(userName string, groupName string) Err {
 		return e
 	}
 	if !userExists {
-		return Err{
-			Code: ec.NotFound,
-			Msg:  fmt.Sprintf("user %s does not exist", userName),
-		}
+		return Err{Code: ec.NotFound, Msg: "user does not exist"}.With(User(userName))
 	}
 	groupExists, e := GroupExists(groupName)
 	if e.Some() {
 		return e
 	}
 	if !groupExists {
-		return Err{
-			Code: ec.NotFound,
-			Msg:  fmt.Sprintf("group %s does not exist", groupName),
-		}
+		return Err{Code: ec.NotFound, Msg: "group does not exist"}.With(UserGroup(groupName))
 	}
 	return NoError
 }
@@ -334,9 +345,7 @@ func AddUserToGroup(userName string, groupName string, verbose ...bool) Err {
 	if len(verbose) > 0 {
 		v = verbose[0]
 	}
-	if v {
-		fmt.Printf("* AddUserToGroup: executing '%s'\n", cmd)
-	}
+	logf(v, "AddUserToGroup: executing '%s'", cmd)
 	return run(cmd, v)
 }
 
@@ -353,9 +362,7 @@ func RemoveUserFromGroup(userName string, groupName string, verbose ...bool) Err
 	if len(verbose) > 0 {
 		v = verbose[0]
 	}
-	if v {
-		fmt.Printf("* RemoveUserFromGroup: executing '%s'\n", cmd)
-	}
+	logf(v, "RemoveUserFromGroup: executing '%s'", cmd)
 	return run(cmd, v)
 }
 
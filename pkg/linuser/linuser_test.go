@@ -7,6 +7,7 @@ import (
 
 	//"github.com/iotanbo/igu/pkg/ec"
 	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/errstest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -20,12 +21,12 @@ func TestGroupExists(t *testing.T) {
 
 		// Group 'sudo' should exist
 		exists, e := GroupExists("tty")
-		expect(t, e.None(), "GroupExists('tty') returned error: %v.", e)
+		errstest.NoErr(t, e, "GroupExists('tty')")
 		expect(t, exists, "Expected GroupExists('tty')==true, got false.")
 
 		// Group 'notExistingGroup' should not exist
 		exists, e = GroupExists("notExistingGroup")
-		expect(t, e.None(), "GroupExists('notExistingGroup') returned error: %v.", e)
+		errstest.NoErr(t, e, "GroupExists('notExistingGroup')")
 		expect(t, !exists, "Expected GroupExists('notExistingGroup')==false, got true.")
 
 	} else { // windows
@@ -38,12 +39,12 @@ func TestUserExists(t *testing.T) {
 
 		// User 'root' should exist
 		exists, e := UserExists("root")
-		expect(t, e.None(), "UserExists('root') returned error: %v.", e)
+		errstest.NoErr(t, e, "UserExists('root')")
 		expect(t, exists, "Expected UserExists('root')==true, got false.")
 
 		// User 'notExistingUser' should not exist
 		exists, e = UserExists("notExistingUser")
-		expect(t, e.None(), "UserExists('notExistingUser') returned error: %v.", e)
+		errstest.NoErr(t, e, "UserExists('notExistingUser')")
 		expect(t, !exists, "Expected UserExists('notExistingUser')==false, got true.")
 
 	} else { // windows
@@ -61,11 +62,11 @@ func TestCreateGroup(t *testing.T) {
 
 		// Create 'dummy_test_group'
 		e := CreateGroup("dummy_test_group", true, 5005)
-		expect(t, e.None(), "CreateGroup('dummy_test_group') returned error: %v.", e)
+		errstest.NoErr(t, e, "CreateGroup('dummy_test_group')")
 
 		// Creating 'sudo' group should return ec.AlreadyExists
 		e = CreateGroup("sudo", true)
-		expect(t, e.Code == ec.AlreadyExists, "CreateGroup('sudo') expected: ec.AlreadyExists, got: %v.", e)
+		errstest.Code(t, e, ec.AlreadyExists, "CreateGroup('sudo')")
 
 	} else { // windows
 		printf("-- Skipping TestCreateGroup on Windows.\n")
@@ -82,11 +83,11 @@ func TestDeleteGroup(t *testing.T) {
 
 		// Delete 'dummy_test_group'
 		e := DeleteGroup("dummy_test_group", true)
-		expect(t, e.None(), "DeleteGroup('dummy_test_group') returned error: %v.", e)
+		errstest.NoErr(t, e, "DeleteGroup('dummy_test_group')")
 
 		// Trying to delete 'nonExistingGroup' should return ec.NotFound
 		e = DeleteGroup("nonExistingGroup", true)
-		expect(t, e.Code == ec.NotFound, "DeleteGroup('nonExistingGroup') expected: ec.NotFound, got: %v.", e)
+		errstest.Code(t, e, ec.NotFound, "DeleteGroup('nonExistingGroup')")
 	} else { // windows
 		printf("-- Skipping TestDeleteGroup on Windows.\n")
 	}
@@ -110,11 +111,11 @@ func TestCreateUser(t *testing.T) {
 			Password:  "test",
 		}
 		e := CreateUser(ud, true)
-		expect(t, e.None(), "CreateUser('testuser') returned error: %v.", e)
+		errstest.NoErr(t, e, "CreateUser('testuser')")
 
 		// Creating same user again should return ec.AlreadyExists
 		e = CreateUser(ud, true)
-		expect(t, e.Code == ec.AlreadyExists, "CreateUser('testuser') expected: ec.AlreadyExists, got: %v.", e)
+		errstest.Code(t, e, ec.AlreadyExists, "CreateUser('testuser')")
 
 	} else { // windows
 		printf("-- Skipping TestCreateUser on Windows.\n")
@@ -131,11 +132,11 @@ func TestDeleteUser(t *testing.T) {
 
 		// Delete 'testuser'
 		e := DeleteUser("testuser", true, true)
-		expect(t, e.None(), "DeleteUser('testuser') returned error: %v.", e)
+		errstest.NoErr(t, e, "DeleteUser('testuser')")
 
 		// Trying to delete 'testuser' once more should return ec.NotFound
 		e = DeleteUser("nonExistingUser", true, true)
-		expect(t, e.Code == ec.NotFound, "DeleteUser('testuser') expected: ec.NotFound, got: %v.", e)
+		errstest.Code(t, e, ec.NotFound, "DeleteUser('testuser')")
 	} else { // windows
 		printf("-- Skipping TestDeleteUser on Windows.\n")
 	}
@@ -149,7 +150,7 @@ func TestAddUserToGroup(t *testing.T) {
 	const userB = "dummyUserB"
 
 	userExists, e := UserExists(userA)
-	expect(t, e.None(), "UserExists(%s): expected NoError, got %v.", userA, e)
+	errstest.NoErr(t, e, "UserExists(%s)", userA)
 	if !userExists {
 		ud := UserDescriptor{
 			UserName:  userA,
@@ -158,11 +159,11 @@ func TestAddUserToGroup(t *testing.T) {
 			GroupId:   55550,
 		}
 		e = CreateUser(ud, true)
-		expect(t, e.None(), "CreateUser(%s): expected NoError, got %v.", userA, e)
+		errstest.NoErr(t, e, "CreateUser(%s)", userA)
 	}
 
 	userExists, e = UserExists(userB)
-	expect(t, e.None(), "UserExists(%s): expected NoError, got %v.", userB, e)
+	errstest.NoErr(t, e, "UserExists(%s)", userB)
 	if !userExists {
 		udb := UserDescriptor{
 			UserName:  userB,
@@ -171,48 +172,45 @@ func TestAddUserToGroup(t *testing.T) {
 			GroupId:   55551,
 		}
 		e = CreateUser(udb, true)
-		expect(t, e.None(), "CreateUser(%s): expected NoError, got %v.", userB, e)
+		errstest.NoErr(t, e, "CreateUser(%s)", userB)
 	}
 
 	// Adding existing user to existing group returns NoError
 	e = AddUserToGroup(userA, userB, true)
-	expect(t, e.None(), "AddUserToGroup(%s, %s): expected NoError, got %v.",
-		userA, userB, e)
+	errstest.NoErr(t, e, "AddUserToGroup(%s, %s)", userA, userB)
 
 	// Adding not-existing user to existing group returns ec.NotFound
 	e = AddUserToGroup("notExists", userB, true)
-	expect(t, e.Code == ec.NotFound, "AddUserToGroup(notExists, %s): expected ec.NotFound, got %v.", userB, e)
+	errstest.Code(t, e, ec.NotFound, "AddUserToGroup(notExists, %s)", userB)
 
 	// Adding existing user to not-existing group returns ec.NotFound
 	e = AddUserToGroup(userA, "groupNotExists", true)
-	expect(t, e.Code == ec.NotFound, "AddUserToGroup(%s, groupNotExists): expected ec.NotFound, got %v.", userA, e)
+	errstest.Code(t, e, ec.NotFound, "AddUserToGroup(%s, groupNotExists)", userA)
 
 	// IsUserInGroup tests
 	// When user and group match, IsUserInGroup returns (true, NoError)
 	inGroup, e := IsUserInGroup(userA, userA, true)
-	expect(t, e.None(), "IsUserInGroup(%s, %s): expected NoError, got %v.",
-		userA, userA, e)
+	errstest.NoErr(t, e, "IsUserInGroup(%s, %s)", userA, userA)
 	expect(t, inGroup, "IsUserInGroup(%s, %s): expected inGroup==true, got %v.", userA, userA, inGroup)
 
 	// When user is in group, IsUserInGroup returns (true, NoError)
 	inGroup, e = IsUserInGroup(userA, userB, true)
-	expect(t, e.None(), "IsUserInGroup(%s, %s): expected NoError, got %v.", userA, userB, e)
+	errstest.NoErr(t, e, "IsUserInGroup(%s, %s)", userA, userB)
 	expect(t, inGroup, "IsUserInGroup(%s, %s): expected inGroup==true, got %v.", userA, userB, inGroup)
 
 	// When user is not in group, IsUserInGroup returns (false, NoError)
 	inGroup, e = IsUserInGroup(userB, userA, true)
-	expect(t, e.None(), "IsUserInGroup(%s, %s): expected NoError, got %v.",
-		userB, userA, e)
+	errstest.NoErr(t, e, "IsUserInGroup(%s, %s)", userB, userA)
 	expect(t, !inGroup, "IsUserInGroup(%s, %s): expected inGroup==false, got %v.",
 		userB, userA, inGroup)
 
 	// When user does not exist, IsUserInGroup returns (false, ec.NotFound)
 	_, e = IsUserInGroup("notExists", userB, true)
-	expect(t, e.Code == ec.NotFound, "IsUserInGroup(notExists, %s): expected ec.NotFound, got %v.", userB, e)
+	errstest.Code(t, e, ec.NotFound, "IsUserInGroup(notExists, %s)", userB)
 
 	// When group does not exist, IsUserInGroup returns (false, ec.NotFound)
 	_, e = IsUserInGroup(userA, "notExists", true)
-	expect(t, e.Code == ec.NotFound, "IsUserInGroup(%s, notExists): expected ec.NotFound, got %v.", userA, e)
+	errstest.Code(t, e, ec.NotFound, "IsUserInGroup(%s, notExists)", userA)
 
 	// RemoveUserFromGroup tests
 	// When removing user from group it does not belong to, returns ec.NothingDone
@@ -222,15 +220,12 @@ func TestAddUserToGroup(t *testing.T) {
 
 	// When removing user from group it belongs to, returns NoError
 	e = RemoveUserFromGroup(userA, userB)
-	expect(t, e.None(), "RemoveUserFromGroup(%s, %s): expected NoError, got %v.",
-		userA, userB, e)
+	errstest.NoErr(t, e, "RemoveUserFromGroup(%s, %s)", userA, userB)
 
 	// Delete users
 	e = DeleteUser(userA, true, true)
-	expect(t, e.None(), "DeleteUser(%s, true, true): expected NoError, got %v.",
-		userA, e)
+	errstest.NoErr(t, e, "DeleteUser(%s, true, true)", userA)
 
 	e = DeleteUser(userB, true, true)
-	expect(t, e.None(), "DeleteUser(%s, true, true): expected NoError, got %v.",
-		userB, e)
+	errstest.NoErr(t, e, "DeleteUser(%s, true, true)", userB)
 }
@@ -0,0 +1,18 @@
+/*
+Package retry repeats operations that fail for temporary reasons.
+Features:
+  - exponential backoff with jitter between attempts;
+  - limits on the number of attempts and on the overall duration
+    (via context deadline);
+  - a policy decides which error codes or groups are retryable,
+    see DefaultRetryable();
+  - errors of all attempts are kept in the chain of the final error
+    as an errs.MultiErr, so it's visible why the retries were used up.
+
+Usage example:
+
+	e := retry.Do(ctx, retry.Policy{MaxAttempts: 5}, func(ctx context.Context) Err {
+		return httputils.Download(url, destPath)
+	})
+*/
+package retry
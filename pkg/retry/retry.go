@@ -0,0 +1,167 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/iotanbo/igu/pkg/ecdef"
+	//lint:ignore ST1001 - for concise error handling.
+	. "github.com/iotanbo/igu/pkg/errs"
+)
+
+// Default values of the Policy fields.
+const (
+	DEFAULT_MAX_ATTEMPTS  = 3
+	DEFAULT_INITIAL_DELAY = 100 * time.Millisecond
+	DEFAULT_MAX_DELAY     = 10 * time.Second
+	DEFAULT_MULTIPLIER    = 2.0
+	DEFAULT_JITTER        = 0.2
+)
+
+// DefaultRetryable returns true if e or any error in its chain
+// is transient, see errs.Transient.
+func DefaultRetryable(e Err) bool {
+	return e.InSet(Transient)
+}
+
+// Policy configures Do(). Zero fields are replaced by defaults.
+type Policy struct {
+	// Maximum number of attempts, including the first one.
+	MaxAttempts int
+	// Delay before the second attempt.
+	InitialDelay time.Duration
+	// Upper limit of the delay between attempts.
+	MaxDelay time.Duration
+	// The delay is multiplied by this factor after each attempt.
+	Multiplier float64
+	// Random deviation of each delay as a fraction of it (0..1),
+	// use a negative value to disable jitter.
+	Jitter float64
+	// Codes and Groups list retryable error codes and names of retryable
+	// error code groups (e.g. ecdef.NET_GROUP); like DefaultRetryable(),
+	// they are matched against e and every error in its chain.
+	// If both are empty and Retryable is nil, DefaultRetryable() decides.
+	Codes  []ecdef.ErrCode
+	Groups []string
+	// Retryable is an additional check, an error is retried if
+	// it matches Codes or Groups or Retryable returns true for it.
+	Retryable func(e Err) bool
+	// OnRetry is called before waiting for the next attempt (optional).
+	OnRetry func(attempt int, e Err, delay time.Duration)
+}
+
+// IsRetryable returns true if e is retryable according to the policy.
+func (p *Policy) IsRetryable(e Err) bool {
+	if len(p.Codes) == 0 && len(p.Groups) == 0 && p.Retryable == nil {
+		return DefaultRetryable(e)
+	}
+	set := NewCodeSet(p.Codes...)
+	for _, group := range p.Groups {
+		set = set.WithGroup(group)
+	}
+	if e.InSet(set) {
+		return true
+	}
+	return p.Retryable != nil && p.Retryable(e)
+}
+
+// Delay returns the delay before attempt number attempt+1
+// (attempts are numbered from 1), jitter not included.
+func (p *Policy) Delay(attempt int) time.Duration {
+	initial, maxDelay, mult := p.InitialDelay, p.MaxDelay, p.Multiplier
+	if initial <= 0 {
+		initial = DEFAULT_INITIAL_DELAY
+	}
+	if maxDelay <= 0 {
+		maxDelay = DEFAULT_MAX_DELAY
+	}
+	if mult < 1 {
+		mult = DEFAULT_MULTIPLIER
+	}
+	d := float64(initial) * math.Pow(mult, float64(attempt-1))
+	if d > float64(maxDelay) {
+		return maxDelay
+	}
+	return time.Duration(d)
+}
+
+func (p *Policy) withJitter(d time.Duration) time.Duration {
+	jitter := p.Jitter
+	if jitter == 0 {
+		jitter = DEFAULT_JITTER
+	}
+	if jitter < 0 {
+		return d
+	}
+	if jitter > 1 {
+		jitter = 1
+	}
+	return time.Duration(float64(d) * (1 + jitter*(2*rand.Float64()-1)))
+}
+
+// Do calls f until it succeeds, returns a non-retryable error,
+// the attempts are used up or ctx is done.
+// Returns NoError on success. A non-retryable error of the first attempt
+// is returned as is; otherwise the returned Err has the code
+// of the last attempt (or the code of the context error) and
+// the errors of all attempts in an *errs.MultiErr cause,
+// the items are indexed by attempt number starting from 1:
+//
+//	ec.TimedOut after 3 attempts: 3 errors: [1] ec.TimedOut; [2] ec.TimedOut; [3] ec.TimedOut
+//
+// Errors of ctx:
+//
+//	ec.DeadlineExceeded // ctx deadline exceeded
+//	ec.Canceled // ctx canceled
+func Do(ctx context.Context, p Policy, f func(ctx context.Context) Err) Err {
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DEFAULT_MAX_ATTEMPTS
+	}
+	var attempts MultiErr
+	for attempt := 1; ; attempt++ {
+		if ctx.Err() != nil {
+			return aborted(&attempts, ctx)
+		}
+		e := f(ctx)
+		if e.None() {
+			return NoError
+		}
+		attempts.AddAt(attempt, e)
+		if !p.IsRetryable(e) || attempt >= maxAttempts {
+			if attempt == 1 {
+				return e
+			}
+			return Err{
+				Code:  e.Code,
+				Msg:   fmt.Sprintf("after %d attempts", attempt),
+				Cause: attempts.ToErr().Cause,
+			}
+		}
+		delay := p.withJitter(p.Delay(attempt))
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, e, delay)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return aborted(&attempts, ctx)
+		case <-timer.C:
+		}
+	}
+}
+
+// aborted returns the error of Do() interrupted by ctx.
+func aborted(attempts *MultiErr, ctx context.Context) Err {
+	code := FromContext(ctx).Code
+	attempts.AddKey("context", ctx.Err())
+	return Err{
+		Code:  code,
+		Msg:   fmt.Sprintf("retry aborted after %d attempts", attempts.Len()-1),
+		Cause: attempts.ToErr().Cause,
+	}
+}
@@ -0,0 +1,120 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/ecdef"
+	"github.com/iotanbo/igu/pkg/echttp"
+	"github.com/iotanbo/igu/pkg/ecnet"
+	//lint:ignore ST1001 - for concise error handling.
+	. "github.com/iotanbo/igu/pkg/errs"
+	"github.com/stretchr/testify/require"
+)
+
+// Policy with short delays and no jitter for testing.
+var fastPolicy = Policy{InitialDelay: time.Millisecond, Jitter: -1}
+
+func TestDo(t *testing.T) {
+	// Success after temporary failures
+	calls := 0
+	var retried []int
+	p := fastPolicy
+	p.OnRetry = func(attempt int, e Err, delay time.Duration) {
+		retried = append(retried, attempt)
+	}
+	e := Do(context.Background(), p, func(ctx context.Context) Err {
+		calls++
+		if calls < 3 {
+			return Err{Code: ec.TimedOut}
+		}
+		return NoError
+	})
+	require.True(t, e.None(), "%v", e)
+	require.Equal(t, 3, calls)
+	require.Equal(t, []int{1, 2}, retried)
+
+	// Non-retryable error is returned as is
+	calls = 0
+	e = Do(context.Background(), fastPolicy, func(ctx context.Context) Err {
+		calls++
+		return Err{Code: ec.NotFound, Msg: "config"}
+	})
+	require.Equal(t, 1, calls)
+	require.Equal(t, Err{Code: ec.NotFound, Msg: "config"}, e)
+
+	// Attempts used up, all of them are in the chain
+	calls = 0
+	e = Do(context.Background(), fastPolicy, func(ctx context.Context) Err {
+		calls++
+		if calls == 2 {
+			return Err{Code: ecnet.ConnectionReset}
+		}
+		return Err{Code: ecnet.ConnectionRefused}
+	})
+	require.Equal(t, DEFAULT_MAX_ATTEMPTS, calls)
+	require.True(t, e.Code == ecnet.ConnectionRefused)
+	require.True(t, errors.Is(e, Code(ecnet.ConnectionReset)))
+	attempts, ok := CauseAs[*MultiErr](e)
+	require.True(t, ok)
+	require.Equal(t, 3, attempts.Len())
+	require.Equal(t, 1, attempts.Items[0].Index)
+	require.Contains(t, e.Error(), "after 3 attempts")
+}
+
+func TestDoContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	calls := 0
+	p := Policy{MaxAttempts: 1000, InitialDelay: 5 * time.Millisecond, Multiplier: 1}
+	e := Do(ctx, p, func(ctx context.Context) Err {
+		calls++
+		return Err{Code: ec.WouldBlock}
+	})
+	require.True(t, e.Code == ec.DeadlineExceeded, "%v", e)
+	require.True(t, calls > 1 && calls < 1000)
+	require.True(t, errors.Is(e, context.DeadlineExceeded))
+	require.True(t, errors.Is(e, Code(ec.WouldBlock)))
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	e = Do(ctx, fastPolicy, func(ctx context.Context) Err {
+		t.Fatal("must not be called with a canceled context")
+		return NoError
+	})
+	require.True(t, e.Code == ec.Canceled, "%v", e)
+}
+
+func TestPolicy(t *testing.T) {
+	var p Policy
+	require.True(t, p.IsRetryable(Err{Code: echttp.ServiceUnavailable_503}))
+	require.False(t, p.IsRetryable(Err{Code: ec.PermissionDenied}))
+	// The whole chain is checked
+	wrapped := Err{Code: ec.Other, Cause: Err{Code: ecnet.ConnectionReset}}
+	require.True(t, p.IsRetryable(wrapped))
+
+	p = Policy{Groups: []string{ecdef.NET_GROUP}, Codes: []ecdef.ErrCode{ec.Dummy}}
+	require.True(t, p.IsRetryable(Err{Code: ecnet.DNSFailure}))
+	require.True(t, p.IsRetryable(Err{Code: ec.Dummy}))
+	require.False(t, p.IsRetryable(Err{Code: ec.TimedOut}))
+	require.True(t, p.IsRetryable(Err{Code: ec.Other, Cause: Err{Code: ec.Dummy}}))
+	require.True(t, p.IsRetryable(wrapped))
+	p.Retryable = func(e Err) bool { return e.Msg == "retry me" }
+	require.True(t, p.IsRetryable(Err{Code: ec.Other, Msg: "retry me"}))
+
+	// Exponential backoff
+	p = Policy{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	require.Equal(t, 100*time.Millisecond, p.Delay(1))
+	require.Equal(t, 200*time.Millisecond, p.Delay(2))
+	require.Equal(t, 800*time.Millisecond, p.Delay(4))
+	require.Equal(t, time.Second, p.Delay(5))
+	// Jitter keeps the delay within the range
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := p.withJitter(100 * time.Millisecond)
+		require.True(t, d >= 50*time.Millisecond && d <= 150*time.Millisecond)
+	}
+}
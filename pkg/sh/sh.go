@@ -5,34 +5,78 @@ package sh
 import (
 	"bytes"
 	"context"
-	"fmt"
+	"io"
 	"os/exec"
 	"strings"
+	"sync/atomic"
 	"time"
 
-	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/lg"
 	//lint:ignore ST1001 - for concise error handling.
 	. "github.com/iotanbo/igu/pkg/errs"
 )
 
+// Non-zero if arguments of executed commands are logged, see SetLogArgs().
+var logArgs int32
+
+// SetLogArgs enables logging of the arguments of executed commands
+// (disabled by default). Arguments may contain secrets, e.g. passwords,
+// enable it only for debugging.
+func SetLogArgs(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&logArgs, v)
+}
+
 // ExecuteCmd executes a shell command and returns stdout and stderr outputs
 // as strings. Optional timeout in milliseconds can be specified.
 //
 // Returned errors:
 //	ec.NoError // completed successfully
 //	ec.ProcessExit // sub-process exited with non-zero error code
-//	ec.TimedOut // timeout occurred
+//	ec.DeadlineExceeded // timeout occurred
 //	ec.PermissionDenied
 // Other errors may be returned for other situations.
+// Names of executed commands are logged with level DEBUG, see package lg;
+// the arguments are logged only if enabled with SetLogArgs().
+// The exit status of the sub-process can be obtained from the cause:
+//	if exitErr, ok := CauseAs[*exec.ExitError](e); ok {
+//		status := exitErr.ExitCode()
+//	}
 //
 // Usage example:
 //	cmd := "sleep"
 //	args := []string{"5"}
 //	stdout, stderr, e := ExecuteCmd(cmd, args, 200)
 func ExecuteCmd(cmd string, args []string, timeout ...int64) (string, string, Err) {
+	return execute(cmd, args, nil, timeout...)
+}
+
+// ExecuteCmdInput is same as ExecuteCmd but writes input to stdin
+// of the command. Secrets (e.g. passwords) passed this way
+// don't appear in the process list and in the logs.
+//
+// Usage example:
+//	_, stderr, e := ExecuteCmdInput("chpasswd", nil, "user:password\n", 10000)
+func ExecuteCmdInput(cmd string, args []string, input string,
+	timeout ...int64) (string, string, Err) {
+	return execute(cmd, args, strings.NewReader(input), timeout...)
+}
+
+func execute(cmd string, args []string, stdin io.Reader,
+	timeout ...int64) (string, string, Err) {
 	// Based on https://stackoverflow.com/a/43246464/3824328
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
+	if lg.Default().Enabled(lg.DEBUG) {
+		if atomic.LoadInt32(&logArgs) != 0 {
+			lg.Debugf("sh: executing '%s'", strings.Join(append([]string{cmd}, args...), " "))
+		} else {
+			lg.Debugf("sh: executing '%s' (%d arguments not logged)", cmd, len(args))
+		}
+	}
 
 	if len(timeout) > 0 {
 		// time.Duration units are nanoseconds, so multiply by 1000000
@@ -42,13 +86,13 @@ func ExecuteCmd(cmd string, args []string, timeout ...int64) (string, string, Er
 		defer cancel()
 
 		c := exec.CommandContext(ctx, cmd, args...)
+		c.Stdin = stdin
 		c.Stdout = &stdout
 		c.Stderr = &stderr
 		if err := c.Run(); err != nil {
-			errMsg := fmt.Sprintf("%v", err)
-			if strings.Contains(errMsg, "signal: killed") {
-				return stdout.String(), stderr.String(),
-					Err{Code: ec.TimedOut, Cause: err}
+			// The process is killed when the deadline passes
+			if e := FromContext(ctx); e.Some() {
+				return stdout.String(), stderr.String(), e
 			}
 			return stdout.String(), stderr.String(),
 				FromError(err)
@@ -56,6 +100,7 @@ func ExecuteCmd(cmd string, args []string, timeout ...int64) (string, string, Er
 		return stdout.String(), stderr.String(), NoError
 	} else {
 		c := exec.Command(cmd, args...)
+		c.Stdin = stdin
 		c.Stdout = &stdout
 		c.Stderr = &stderr
 		if err := c.Run(); err != nil {
@@ -74,7 +119,7 @@ func ExecuteCmd(cmd string, args []string, timeout ...int64) (string, string, Er
 //	ec.NoError // completed successfully
 //	ec.Syntax // cmd contains syntax errors
 //	ec.ProcessExit // sub-process exited with non-zero error code
-//	ec.TimedOut // timeout occurred
+//	ec.DeadlineExceeded // timeout occurred
 //	ec.PermissionDenied
 // Other errors may be returned for other situations.
 //
@@ -1,11 +1,14 @@
 package sh
 
 import (
+	"bytes"
 	"fmt"
 	"runtime"
 	"testing"
 
 	"github.com/iotanbo/igu/pkg/ec"
+	"github.com/iotanbo/igu/pkg/errstest"
+	"github.com/iotanbo/igu/pkg/lg"
 	"github.com/stretchr/testify/require"
 )
 
@@ -20,16 +23,14 @@ func TestExecuteCmd(t *testing.T) {
 		cmd := "ls"
 		args := []string{"-la"}
 		stdout, stderr, e := ExecuteCmd(cmd, args)
-		expect(t, e.None(), "* expected e.None(), got: "+
-			"'%s', '%s', '%v'\n", stdout, stderr, e)
+		errstest.NoErr(t, e, "stdout: '%s', stderr: '%s'", stdout, stderr)
 		expect(t, len(stderr) == 0)
 
 		// Test with timeout
 		cmd = "sleep"
 		args = []string{"5"}
 		stdout, stderr, e = ExecuteCmd(cmd, args, 200)
-		expect(t, e.Code == ec.TimedOut, "* expected ec.TimedOut, got: "+
-			"'%s', '%s', '%v'\n", stdout, stderr, e)
+		errstest.Code(t, e, ec.DeadlineExceeded, "stdout: '%s', stderr: '%s'", stdout, stderr)
 
 		// TODO: Test with timeout that is not exceeded
 	} else { // windows
@@ -44,15 +45,13 @@ func TestExecuteLine(t *testing.T) {
 		printf("-- Running tests on ...nix.\n")
 		cmdLine := " ls -la"
 		stdout, stderr, e := ExecuteLine(cmdLine)
-		expect(t, e.None(), "* expected e.None(), got: "+
-			"'%s', '%s', '%v'\n", stdout, stderr, e)
+		errstest.NoErr(t, e, "stdout: '%s', stderr: '%s'", stdout, stderr)
 		expect(t, len(stderr) == 0)
 
 		// Test with timeout
 		cmdLine = "sleep 5"
 		stdout, stderr, e = ExecuteLine(cmdLine, 200)
-		expect(t, e.Code == ec.TimedOut, "* expected ec.TimedOut, got: "+
-			"'%s', '%s', '%v'\n", stdout, stderr, e)
+		errstest.Code(t, e, ec.DeadlineExceeded, "stdout: '%s', stderr: '%s'", stdout, stderr)
 
 		// TODO: Test with timeout that is not exceeded
 	} else { // windows
@@ -60,3 +59,26 @@ func TestExecuteLine(t *testing.T) {
 	}
 
 }
+
+func TestExecuteCmdInput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires unix tools")
+	}
+	var log bytes.Buffer
+	defer lg.SetDefault(lg.Default())
+	lg.SetDefault(lg.New(&log, lg.DEBUG))
+
+	stdout, _, e := ExecuteCmdInput("cat", nil, "user:secret\n")
+	errstest.NoErr(t, e)
+	require.Equal(t, "user:secret\n", stdout)
+
+	// Arguments are logged only if enabled.
+	_, _, e = ExecuteCmd("echo", []string{"secret"})
+	errstest.NoErr(t, e)
+	require.NotContains(t, log.String(), "secret")
+	SetLogArgs(true)
+	defer SetLogArgs(false)
+	_, _, e = ExecuteCmd("echo", []string{"secret"})
+	errstest.NoErr(t, e)
+	require.Contains(t, log.String(), "echo secret")
+}
@@ -6,7 +6,6 @@ package zip
 // https://snyk.io/research/zip-slip-vulnerability
 import (
 	"compress/flate"
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,6 +14,7 @@ import (
 
 	"github.com/iotanbo/igu/pkg/ec"
 	"github.com/iotanbo/igu/pkg/fu"
+	"github.com/iotanbo/igu/pkg/lg"
 
 	//lint:ignore ST1001 - for concise error handling.
 	. "github.com/iotanbo/igu/pkg/errs"
@@ -54,14 +54,14 @@ func Zip(srcPath string, destPath string) Err {
 		return e
 	}
 	if !exists {
-		return Err{Code: ec.NotFound, Msg: "source " + srcPath}
+		return Err{Code: ec.NotFound, Msg: "source"}.With(Path(srcPath))
 	}
 	exists, _, e = fu.PathExists(destPath)
 	if e.Some() {
 		return e
 	}
 	if exists {
-		return Err{Code: ec.AlreadyExists, Msg: "destination " + destPath}
+		return Err{Code: ec.AlreadyExists, Msg: "destination"}.With(Path(destPath))
 	}
 
 	z := archiver.Zip{
@@ -74,7 +74,7 @@ func Zip(srcPath string, destPath string) Err {
 	}
 
 	if err := z.Archive([]string{srcPath}, destPath); err != nil {
-		return FromError(err)
+		return FromError(err).With(Op("zip"), Path(srcPath))
 	}
 
 	return NoError
@@ -92,7 +92,7 @@ func Unarchive(srcPath, destPath string,
 		return e
 	}
 	if !srcExists {
-		return Err{Code: ec.NotFound, Msg: "source " + srcPath}
+		return Err{Code: ec.NotFound, Msg: "source"}.With(Path(srcPath))
 	}
 	destExists, _, e := fu.PathExists(destPath)
 	if e.Some() {
@@ -100,24 +100,24 @@ func Unarchive(srcPath, destPath string,
 	}
 
 	if destExists {
-		fmt.Printf("* Destination already exists: '%s'\n", destPath)
+		lg.Debugf("zip: destination already exists: '%s'", destPath)
 		// In NoOverwrite mode return ec.AlreadyExists
 		if overwriteMode == NoOverwrite {
-			return Err{Code: ec.AlreadyExists, Msg: "dest " + srcPath}
+			return Err{Code: ec.AlreadyExists, Msg: "destination"}.With(Path(destPath))
 		}
 		// In HardOverwrite mode remove destination and all its contents
 		if overwriteMode == FullOverwrite {
 			if err := os.RemoveAll(destPath); err != nil {
 				rmError := FromError(err)
-				rmError.Msg = "failed to remove destination " + destPath
-				return rmError
+				rmError.Msg = "failed to remove destination"
+				return rmError.With(Path(destPath))
 			}
 		}
 	}
 	os.MkdirAll(destPath, 0755)
 
 	if err := archiver.Unarchive(srcPath, destPath); err != nil {
-		return FromError(err)
+		return FromError(err).With(Op("unarchive"), Path(srcPath))
 	}
 
 	//fu.Copy(fu.NO_OVERWRITE)
@@ -136,7 +136,7 @@ func UnTarGzip(srcPath, destPath string,
 		return e
 	}
 	if !srcExists {
-		return Err{Code: ec.NotFound, Msg: "source " + srcPath}
+		return Err{Code: ec.NotFound, Msg: "source"}.With(Path(srcPath))
 	}
 	destExists, _, e := fu.PathExists(destPath)
 	if e.Some() {
@@ -144,17 +144,17 @@ func UnTarGzip(srcPath, destPath string,
 	}
 
 	if destExists {
-		fmt.Printf("* Destination already exists: '%s'\n", destPath)
+		lg.Debugf("zip: destination already exists: '%s'", destPath)
 		// In NoOverwrite mode return ec.AlreadyExists
 		if overwriteMode == NoOverwrite {
-			return Err{Code: ec.AlreadyExists, Msg: "dest " + srcPath}
+			return Err{Code: ec.AlreadyExists, Msg: "destination"}.With(Path(destPath))
 		}
 		// In HardOverwrite mode remove destination and all its contents
 		if overwriteMode == FullOverwrite {
 			if err := os.RemoveAll(destPath); err != nil {
 				rmError := FromError(err)
-				rmError.Msg = "failed to remove destination " + destPath
-				return rmError
+				rmError.Msg = "failed to remove destination"
+				return rmError.With(Path(destPath))
 			}
 		}
 	}